@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"text/template"
@@ -80,10 +81,43 @@ func NewCmdBundle() *cobra.Command { //nolint:funlen // cobra command builders a
 	bundleBuildCmd := &cobra.Command{
 		Use:   "build [path]",
 		Short: "Build schemas and generate IaC files from massdriver.yaml file",
+		Long:  helpdocs.MustRender("bundle/build"),
 		Args:  cobra.MaximumNArgs(1),
 		RunE:  runBundleBuild,
 	}
 	bundleBuildCmd.Flags().StringP("bundle-directory", "b", ".", "Path to a directory containing a massdriver.yaml file.")
+	bundleBuildCmd.Flags().Bool("generate-outputs", false, "Scaffold artifact output declarations (e.g. _massdriver_outputs.tf) in steps that don't have them yet.")
+	bundleBuildCmd.Flags().BoolP("watch", "w", false, "Rebuild and lint whenever massdriver.yaml, a local $ref file or a step directory changes")
+
+	bundleAppCmd := &cobra.Command{
+		Use:   "app",
+		Short: "Work with the app configuration of application bundles",
+	}
+
+	bundleAppEnvsCmd := &cobra.Command{
+		Use:   "envs [path]",
+		Short: "Evaluate the app.envs jq queries of a bundle locally",
+		Long:  helpdocs.MustRender("bundle/app-envs"),
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  runBundleAppEnvs,
+	}
+	bundleAppEnvsCmd.Flags().StringP("bundle-directory", "b", ".", "Path to a directory containing a massdriver.yaml file.")
+	bundleAppEnvsCmd.Flags().StringP("params", "p", "", "Params file (.json, .yaml or .tfvars)")
+	bundleAppEnvsCmd.Flags().StringP("connections", "c", "", "File of connection artifacts keyed by connection name")
+	bundleAppEnvsCmd.Flags().StringP("metadata", "m", "", "File with the md_metadata to use (default: generated placeholders)")
+	bundleAppEnvsCmd.Flags().StringP("output", "o", "text", "Output format (text, json)")
+
+	bundleDocsCmd := &cobra.Command{
+		Use:   "docs [path]",
+		Short: "Generate markdown docs for a bundle from its massdriver.yaml",
+		Long:  helpdocs.MustRender("bundle/docs"),
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  runBundleDocs,
+	}
+	bundleDocsCmd.Flags().StringP("bundle-directory", "b", ".", "Path to a directory containing a massdriver.yaml file.")
+	bundleDocsCmd.Flags().StringP("output", "o", "", "File to write the docs to instead of printing them")
+	bundleDocsCmd.Flags().StringP("inject", "i", "", "Markdown file (e.g. README.md) to inject the docs into, between its marker comments")
+	bundleDocsCmd.MarkFlagsMutuallyExclusive("output", "inject")
 
 	bundleImportCmd := &cobra.Command{
 		Use:   "import [path]",
@@ -94,6 +128,8 @@ func NewCmdBundle() *cobra.Command { //nolint:funlen // cobra command builders a
 	}
 	bundleImportCmd.Flags().StringP("bundle-directory", "b", ".", "Path to a directory containing a massdriver.yaml file.")
 	bundleImportCmd.Flags().BoolP("all", "a", false, "Import all variables without prompting")
+	bundleImportCmd.Flags().Bool("dry-run", false, "Print a diff of the proposed massdriver.yaml change without writing it")
+	bundleImportCmd.Flags().StringP("output", "o", cmdbundle.ImportOutputFile, "Where to send the change (file, patch). patch prints a patch to stdout instead of writing massdriver.yaml")
 
 	bundleLintCmd := &cobra.Command{
 		Use:   "lint [path]",
@@ -103,6 +139,66 @@ func NewCmdBundle() *cobra.Command { //nolint:funlen // cobra command builders a
 	}
 	bundleLintCmd.Flags().StringP("bundle-directory", "b", ".", "Path to a directory containing a massdriver.yaml file.")
 
+	bundleMigrateCmd := &cobra.Command{
+		Use:   "migrate [path]",
+		Short: "Rewrite legacy massdriver.yaml fields to the current bundle spec",
+		Long:  helpdocs.MustRender("bundle/migrate"),
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  runBundleMigrate,
+	}
+	bundleMigrateCmd.Flags().StringP("bundle-directory", "b", ".", "Path to a directory containing a massdriver.yaml file.")
+	bundleMigrateCmd.Flags().Bool("dry-run", false, "Print a diff of the migrated massdriver.yaml without writing it")
+	bundleMigrateCmd.Flags().String("version", cmdbundle.DefaultMigrateVersion, "Version to set when massdriver.yaml has none")
+
+	bundlePlanStepsCmd := &cobra.Command{
+		Use:   "plan-steps [path]",
+		Short: "Print the order in which bundle steps run for a deployment action",
+		Long:  helpdocs.MustRender("bundle/plan-steps"),
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  runBundlePlanSteps,
+	}
+	bundlePlanStepsCmd.Flags().StringP("bundle-directory", "b", ".", "Path to a directory containing a massdriver.yaml file.")
+	bundlePlanStepsCmd.Flags().String("action", bundle.PlanActionProvision, "Deployment action to plan (provision, decommission)")
+
+	bundlePreviewFormCmd := &cobra.Command{
+		Use:   "preview-form [path]",
+		Short: "Preview the params form of a built bundle in your browser",
+		Long:  helpdocs.MustRender("bundle/preview-form"),
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  runBundlePreviewForm,
+	}
+	bundlePreviewFormCmd.Flags().StringP("bundle-directory", "b", ".", "Path to a directory containing a massdriver.yaml file.")
+	bundlePreviewFormCmd.Flags().IntP("port", "p", 0, "Localhost port to serve the form on (default: any free port)")
+	bundlePreviewFormCmd.Flags().StringP("output", "o", "params.json", "Path to write submitted params to (.json or .yaml)")
+
+	bundleParamsCmd := &cobra.Command{
+		Use:   "params",
+		Short: "Work with bundle params",
+	}
+
+	bundleParamsExampleCmd := &cobra.Command{
+		Use:   "example [path|name@version]",
+		Short: "Generate an example params file from a bundle's params schema",
+		Long:  helpdocs.MustRender("bundle/params-example"),
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  runBundleParamsExample,
+	}
+	bundleParamsExampleCmd.Flags().StringP("output", "o", "json", "Output format (json, yaml, tfvars)")
+	bundleParamsExampleCmd.Flags().Bool("required-only", false, "Only include params the schema requires")
+
+	bundleTestCmd := &cobra.Command{
+		Use:   "test [path]",
+		Short: "Run a bundle's test cases against a local build",
+		Long:  helpdocs.MustRender("bundle/test"),
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  runBundleTest,
+	}
+	bundleTestCmd.Flags().StringP("bundle-directory", "b", ".", "Path to a directory containing a massdriver.yaml file.")
+	bundleTestCmd.Flags().StringP("tests-directory", "t", "", "Directory of test cases (default: the bundle's tests directory)")
+	bundleTestCmd.Flags().String("run", "", "Only run test cases whose names match this regular expression")
+	bundleTestCmd.Flags().BoolP("update", "u", false, "Update golden files with the generated output instead of comparing them")
+	bundleTestCmd.Flags().String("junit", "", "Write a JUnit XML report to this file")
+
 	var bundleNewInput bundleNew
 
 	bundleNewCmd := &cobra.Command{
@@ -178,13 +274,22 @@ func NewCmdBundle() *cobra.Command { //nolint:funlen // cobra command builders a
 
 	bundleCmd.AddCommand(bundleListCmd)
 	bundleCmd.AddCommand(bundleBuildCmd)
+	bundleCmd.AddCommand(bundleAppCmd)
+	bundleAppCmd.AddCommand(bundleAppEnvsCmd)
+	bundleCmd.AddCommand(bundleDocsCmd)
 	bundleCmd.AddCommand(bundleImportCmd)
 	bundleCmd.AddCommand(bundleLintCmd)
+	bundleCmd.AddCommand(bundleMigrateCmd)
 	bundleCmd.AddCommand(bundleNewCmd)
+	bundleCmd.AddCommand(bundleParamsCmd)
+	bundleParamsCmd.AddCommand(bundleParamsExampleCmd)
+	bundleCmd.AddCommand(bundlePlanStepsCmd)
+	bundleCmd.AddCommand(bundlePreviewFormCmd)
 	bundleCmd.AddCommand(bundleCreateCmd)
 	bundleCmd.AddCommand(bundlePublishCmd)
 	bundleCmd.AddCommand(bundleGetCmd)
 	bundleCmd.AddCommand(bundlePullCmd)
+	bundleCmd.AddCommand(bundleTestCmd)
 	bundleCmd.AddCommand(bundleTemplateCmd)
 	bundleTemplateCmd.AddCommand(bundleTemplateListCmd)
 	return bundleCmd
@@ -351,19 +456,35 @@ func runBundleBuild(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-	cmd.SilenceUsage = true
-
-	unmarshalledBundle, err := bundle.Unmarshal(bundleDirectory)
+	generateOutputs, err := cmd.Flags().GetBool("generate-outputs")
 	if err != nil {
 		return err
 	}
+	watch, err := cmd.Flags().GetBool("watch")
+	if err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
 
 	mdClient, err := massdriver.NewClient()
 	if err != nil {
 		return fmt.Errorf("error initializing massdriver client: %w", err)
 	}
 
-	return cmdbundle.RunBuild(bundleDirectory, unmarshalledBundle, mdClient)
+	if watch {
+		ctx, cancel := signalContext(context.Background())
+		defer cancel()
+		return cmdbundle.RunBuildWatch(ctx, bundleDirectory, mdClient, cmdbundle.WatchOptions{
+			Build: bundle.BuildOptions{GenerateOutputs: generateOutputs},
+		})
+	}
+
+	unmarshalledBundle, err := bundle.Unmarshal(bundleDirectory)
+	if err != nil {
+		return err
+	}
+
+	return cmdbundle.RunBuild(bundleDirectory, unmarshalledBundle, mdClient, bundle.BuildOptions{GenerateOutputs: generateOutputs})
 }
 
 func runBundleImport(cmd *cobra.Command, args []string) error {
@@ -375,9 +496,47 @@ func runBundleImport(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	if err != nil {
+		return err
+	}
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+
+	// connections are only detected when resource types can be fetched
+	mdClient := optionalClient("unable to fetch resource types, variables won't be matched to connections")
+
+	return cmdbundle.RunImport(bundleDirectory, cmdbundle.ImportOptions{
+		SkipVerify: skipVerify,
+		DryRun:     dryRun,
+		Output:     output,
+		MDClient:   mdClient,
+	})
+}
+
+func runBundleMigrate(cmd *cobra.Command, args []string) error {
+	bundleDirectory, err := bundleDir(cmd, args)
+	if err != nil {
+		return err
+	}
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	if err != nil {
+		return err
+	}
+	version, err := cmd.Flags().GetString("version")
+	if err != nil {
+		return err
+	}
 	cmd.SilenceUsage = true
 
-	return cmdbundle.RunImport(bundleDirectory, skipVerify)
+	_, err = cmdbundle.RunMigrate(bundleDirectory, cmdbundle.MigrateOptions{
+		Version: version,
+		DryRun:  dryRun,
+	})
+	return err
 }
 
 func runBundleLint(cmd *cobra.Command, args []string) error {
@@ -416,6 +575,172 @@ func runBundleLint(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runBundlePlanSteps(cmd *cobra.Command, args []string) error {
+	bundleDirectory, err := bundleDir(cmd, args)
+	if err != nil {
+		return err
+	}
+	action, err := cmd.Flags().GetString("action")
+	if err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+
+	unmarshalledBundle, err := bundle.Unmarshal(bundleDirectory)
+	if err != nil {
+		return err
+	}
+
+	return cmdbundle.RunPlanSteps(unmarshalledBundle, action)
+}
+
+func runBundleAppEnvs(cmd *cobra.Command, args []string) error {
+	bundleDirectory, err := bundleDir(cmd, args)
+	if err != nil {
+		return err
+	}
+	paramsPath, err := cmd.Flags().GetString("params")
+	if err != nil {
+		return err
+	}
+	connectionsPath, err := cmd.Flags().GetString("connections")
+	if err != nil {
+		return err
+	}
+	metadataPath, err := cmd.Flags().GetString("metadata")
+	if err != nil {
+		return err
+	}
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+
+	unmarshalledBundle, err := bundle.Unmarshal(bundleDirectory)
+	if err != nil {
+		return err
+	}
+
+	return cmdbundle.RunAppEnvs(unmarshalledBundle, cmdbundle.AppEnvsOptions{
+		ParamsPath:      paramsPath,
+		ConnectionsPath: connectionsPath,
+		MetadataPath:    metadataPath,
+		Output:          output,
+	})
+}
+
+func runBundleDocs(cmd *cobra.Command, args []string) error {
+	bundleDirectory, err := bundleDir(cmd, args)
+	if err != nil {
+		return err
+	}
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return err
+	}
+	inject, err := cmd.Flags().GetString("inject")
+	if err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+
+	mdClient := optionalClient("unable to connect to Massdriver, resource type $refs can't be resolved")
+
+	return cmdbundle.RunDocs(bundleDirectory, mdClient, cmdbundle.DocsOptions{
+		OutputPath: output,
+		InjectPath: inject,
+	})
+}
+
+func runBundleTest(cmd *cobra.Command, args []string) error {
+	bundleDirectory, err := bundleDir(cmd, args)
+	if err != nil {
+		return err
+	}
+	testsDirectory, err := cmd.Flags().GetString("tests-directory")
+	if err != nil {
+		return err
+	}
+	run, err := cmd.Flags().GetString("run")
+	if err != nil {
+		return err
+	}
+	update, err := cmd.Flags().GetBool("update")
+	if err != nil {
+		return err
+	}
+	junit, err := cmd.Flags().GetString("junit")
+	if err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+
+	mdClient := optionalClient("unable to connect to Massdriver, resource type $refs can't be resolved")
+
+	return cmdbundle.RunTest(bundleDirectory, mdClient, cmdbundle.TestOptions{
+		TestsDir:  testsDirectory,
+		Run:       run,
+		Update:    update,
+		JUnitPath: junit,
+	})
+}
+
+func runBundleParamsExample(cmd *cobra.Command, args []string) error {
+	source := "."
+	if len(args) > 0 {
+		source = args[0]
+	}
+	format, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(cmdbundle.ParamsExampleFormats, format) {
+		return fmt.Errorf("unsupported output format %q, use one of %s", format, strings.Join(cmdbundle.ParamsExampleFormats, ", "))
+	}
+	requiredOnly, err := cmd.Flags().GetBool("required-only")
+	if err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+
+	mdClient := optionalClient("unable to connect to Massdriver, resource type $refs can't be resolved")
+
+	return cmdbundle.RunParamsExample(context.Background(), mdClient, source, cmdbundle.ParamsExampleOptions{
+		Format:       format,
+		RequiredOnly: requiredOnly,
+	})
+}
+
+func runBundlePreviewForm(cmd *cobra.Command, args []string) error {
+	bundleDirectory, err := bundleDir(cmd, args)
+	if err != nil {
+		return err
+	}
+	port, err := cmd.Flags().GetInt("port")
+	if err != nil {
+		return err
+	}
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return err
+	}
+	switch filepath.Ext(output) {
+	case ".json", ".yaml":
+	default:
+		return fmt.Errorf("unsupported params file type %q, use .json or .yaml", filepath.Ext(output))
+	}
+	cmd.SilenceUsage = true
+
+	ctx, cancel := signalContext(context.Background())
+	defer cancel()
+
+	return cmdbundle.RunPreviewForm(ctx, bundleDirectory, cmdbundle.PreviewFormOptions{
+		Address:    fmt.Sprintf("127.0.0.1:%d", port),
+		OutputPath: output,
+	})
+}
+
 func runBundlePublish(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
@@ -653,3 +978,15 @@ func renderBundle(b *types.Bundle, mdClient *massdriver.Client) error {
 	fmt.Print(out)
 	return nil
 }
+
+// optionalClient returns a Massdriver client for commands that can run without one. When
+// the client can't be created, it warns with consequence and the client error and
+// returns nil.
+func optionalClient(consequence string) *massdriver.Client {
+	mdClient, err := massdriver.NewClient()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, prettylogs.Orange(fmt.Sprintf("Warning: %s: %s", consequence, err)))
+		return nil
+	}
+	return mdClient
+}
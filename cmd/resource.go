@@ -33,8 +33,10 @@ func NewCmdResource() *cobra.Command {
 
 	resourceCmd.AddCommand(newResourceCreateCmd())
 	resourceCmd.AddCommand(newResourceGetCmd())
+	resourceCmd.AddCommand(newResourceImportDirCmd())
 	resourceCmd.AddCommand(newResourceDownloadCmd())
 	resourceCmd.AddCommand(newResourceUpdateCmd())
+	resourceCmd.AddCommand(newResourceUsagesCmd())
 	resourceCmd.AddCommand(newResourceDeleteCmd())
 	resourceCmd.AddCommand(newResourceListCmd())
 
@@ -94,6 +96,19 @@ func newResourceDownloadCmd() *cobra.Command {
 	return resourceDownloadCmd
 }
 
+func newResourceImportDirCmd() *cobra.Command {
+	resourceImportDirCmd := &cobra.Command{
+		Use:   "import-dir <dir>",
+		Short: "Create or update resources from a directory of resource files",
+		Long:  helpdocs.MustRender("resource/import-dir"),
+		Args:  cobra.ExactArgs(1),
+		RunE:  runResourceImportDir,
+	}
+	resourceImportDirCmd.Flags().Bool("dry-run", false, "Print the plan without creating or updating resources")
+	resourceImportDirCmd.Flags().BoolP("yes", "y", false, "Skip confirmation prompt")
+	return resourceImportDirCmd
+}
+
 func newResourceUpdateCmd() *cobra.Command {
 	resourceUpdateCmd := &cobra.Command{
 		Use:   "update [resource-id]",
@@ -105,14 +120,28 @@ func newResourceUpdateCmd() *cobra.Command {
   mass resource update 12345678-1234-1234-1234-123456789012 -f resource.json
 
   # Update resource payload and rename
-  mass resource update 12345678-1234-1234-1234-123456789012 -f resource.json -n new-name`,
+  mass resource update 12345678-1234-1234-1234-123456789012 -f resource.json -n new-name
+
+  # Skip the confirmation prompt
+  mass resource update 12345678-1234-1234-1234-123456789012 -f resource.json --yes`,
 	}
 	resourceUpdateCmd.Flags().StringP("name", "n", "", "New resource name")
 	resourceUpdateCmd.Flags().StringP("file", "f", "", "Resource payload file")
+	resourceUpdateCmd.Flags().BoolP("yes", "y", false, "Skip confirmation prompt")
 	_ = resourceUpdateCmd.MarkFlagRequired("file")
 	return resourceUpdateCmd
 }
 
+func newResourceUsagesCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "usages [resource-id]",
+		Short: "List the environments and instances that depend on a resource",
+		Long:  helpdocs.MustRender("resource/usages"),
+		Args:  cobra.ExactArgs(1),
+		RunE:  runResourceUsages,
+	}
+}
+
 func newResourceDeleteCmd() *cobra.Command {
 	resourceDeleteCmd := &cobra.Command{
 		Use:   "delete [resource-id]",
@@ -122,10 +151,10 @@ func newResourceDeleteCmd() *cobra.Command {
 		Example: `  # Delete an imported resource
   mass resource delete 12345678-1234-1234-1234-123456789012
 
-  # Skip the confirmation prompt
+  # Skip the confirmation prompt, and delete it even if environments or instances use it
   mass resource delete 12345678-1234-1234-1234-123456789012 --force`,
 	}
-	resourceDeleteCmd.Flags().BoolP("force", "f", false, "Skip confirmation prompt")
+	resourceDeleteCmd.Flags().BoolP("force", "f", false, "Skip confirmation prompt and delete even if the resource is in use")
 	return resourceDeleteCmd
 }
 
@@ -280,6 +309,28 @@ func runResourceCreate(cmd *cobra.Command, args []string) error {
 	return createErr
 }
 
+func runResourceImportDir(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	if err != nil {
+		return err
+	}
+	yes, err := cmd.Flags().GetBool("yes")
+	if err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+
+	mdClient, err := massdriver.NewClient()
+	if err != nil {
+		return fmt.Errorf("error initializing massdriver client: %w", err)
+	}
+
+	_, importErr := resource.RunImportDir(ctx, resource.NewAPI(mdClient), args[0], resource.ImportDirOptions{DryRun: dryRun, Yes: yes})
+	return importErr
+}
+
 func runResourceUpdate(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
@@ -292,6 +343,10 @@ func runResourceUpdate(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	yes, err := cmd.Flags().GetBool("yes")
+	if err != nil {
+		return err
+	}
 	cmd.SilenceUsage = true
 
 	mdClient, err := massdriver.NewClient()
@@ -299,7 +354,7 @@ func runResourceUpdate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("error initializing massdriver client: %w", err)
 	}
 
-	_, updateErr := resource.RunUpdate(ctx, resource.NewAPI(mdClient), resourceID, resourceName, resourceFile)
+	_, updateErr := resource.RunUpdate(ctx, resource.NewAPI(mdClient), resourceID, resourceName, resourceFile, yes, os.Stdin)
 	return updateErr
 }
 
@@ -321,6 +376,21 @@ func runResourceDelete(cmd *cobra.Command, args []string) error {
 	return resource.RunDelete(ctx, resource.NewAPI(mdClient), resourceID, force, os.Stdin)
 }
 
+func runResourceUsages(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	resourceID := args[0]
+	cmd.SilenceUsage = true
+
+	mdClient, err := massdriver.NewClient()
+	if err != nil {
+		return fmt.Errorf("error initializing massdriver client: %w", err)
+	}
+
+	_, usagesErr := resource.RunUsages(ctx, resource.NewAPI(mdClient), resourceID, os.Stdout)
+	return usagesErr
+}
+
 func runResourceGet(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
@@ -8,15 +8,18 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"text/template"
 
 	"github.com/charmbracelet/glamour"
 	"github.com/massdriver-cloud/mass/docs/helpdocs"
 	"github.com/massdriver-cloud/mass/internal/cli"
+	"github.com/massdriver-cloud/mass/internal/files"
 	"github.com/massdriver-cloud/mass/internal/prettylogs"
 	"github.com/massdriver-cloud/mass/internal/resourcetype"
 	"github.com/massdriver-cloud/massdriver-sdk-go/massdriver"
+	"github.com/massdriver-cloud/massdriver-sdk-go/massdriver/platform/resources"
 	"github.com/spf13/cobra"
 )
 
@@ -57,6 +60,26 @@ func NewCmdType() *cobra.Command {
 		Args:  cobra.ExactArgs(1),
 		RunE:  runTypePublish,
 	}
+	typePublishCmd.Flags().Bool("oci", false, "Package the resource type directory and publish it to the registry as a versioned artifact")
+	typePublishCmd.Flags().Bool("check-compat", false, "Check that existing resources of this type still validate against the new schema before publishing")
+
+	typePullCmd := &cobra.Command{
+		Use:   "pull <name>@<version>",
+		Short: "Pull a resource type from the registry to a local directory",
+		Long:  helpdocs.MustRender("type/pull"),
+		Args:  cobra.ExactArgs(1),
+		RunE:  runTypePull,
+	}
+	typePullCmd.Flags().StringP("directory", "d", "", "Directory to output the resource type. Defaults to the resource type name.")
+	typePullCmd.Flags().BoolP("force", "f", false, "Force pull even if the directory already exists. This will overwrite existing files.")
+
+	typeDiffCmd := &cobra.Command{
+		Use:   "diff <name> <file>",
+		Short: "Compare a published resource type with a local one",
+		Long:  helpdocs.MustRender("type/diff"),
+		Args:  cobra.ExactArgs(2),
+		RunE:  runTypeDiff,
+	}
 
 	typeDeleteCmd := &cobra.Command{
 		Use:   "delete [resource-type]",
@@ -67,8 +90,43 @@ func NewCmdType() *cobra.Command {
 	}
 	typeDeleteCmd.Flags().BoolP("force", "f", false, "Skip confirmation prompt")
 
+	typeNewCmd := &cobra.Command{
+		Use:   "new <name>",
+		Short: "Scaffold a new resource type",
+		Long:  helpdocs.MustRender("type/new"),
+		Args:  cobra.ExactArgs(1),
+		RunE:  runTypeNew,
+	}
+	typeNewCmd.Flags().StringP("label", "l", "", "Label shown in the Massdriver UI (defaults to the name in title case)")
+	typeNewCmd.Flags().StringP("output-directory", "d", "", "Directory to create the resource type in (defaults to the name)")
+
+	typeBuildCmd := &cobra.Command{
+		Use:   "build [path]",
+		Short: "Print the resource type a massdriver.yaml builds into",
+		Long:  helpdocs.MustRender("type/build"),
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  runTypeBuild,
+	}
+
+	typeRenderExportCmd := &cobra.Command{
+		Use:   "render-export <path>",
+		Short: "Render a resource type's export template locally",
+		Long:  helpdocs.MustRender("type/render-export"),
+		Args:  cobra.ExactArgs(1),
+		RunE:  runTypeRenderExport,
+	}
+	typeRenderExportCmd.Flags().StringP("format", "f", "", "File format of the export to render (required when there are several)")
+	typeRenderExportCmd.Flags().StringP("data", "d", "", "Path to a JSON or YAML file of resource data to render with")
+	typeRenderExportCmd.Flags().Bool("strict", false, "Fail on template variables missing from the data instead of rendering them empty")
+	_ = typeRenderExportCmd.MarkFlagRequired("data")
+
+	typeCmd.AddCommand(typeBuildCmd)
+	typeCmd.AddCommand(typeDiffCmd)
 	typeCmd.AddCommand(typeGetCmd)
+	typeCmd.AddCommand(typeNewCmd)
 	typeCmd.AddCommand(typePublishCmd)
+	typeCmd.AddCommand(typePullCmd)
+	typeCmd.AddCommand(typeRenderExportCmd)
 	typeCmd.AddCommand(typeListCmd)
 	typeCmd.AddCommand(typeDeleteCmd)
 
@@ -114,10 +172,103 @@ func runTypeGet(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runTypeNew(cmd *cobra.Command, args []string) error {
+	label, err := cmd.Flags().GetString("label")
+	if err != nil {
+		return err
+	}
+	outputDir, err := cmd.Flags().GetString("output-directory")
+	if err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+
+	dir, err := resourcetype.New(resourcetype.NewOptions{
+		Name:      args[0],
+		Label:     label,
+		OutputDir: outputDir,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Resource type %s created in %s\n", prettylogs.Underline(args[0]), dir)
+	fmt.Printf("Run `mass resource-type build %s` to see the resource type it builds.\n", dir)
+	return nil
+}
+
+func runTypeBuild(cmd *cobra.Command, args []string) error {
+	path := "."
+	if len(args) > 0 {
+		path = args[0]
+	}
+	if info, statErr := os.Stat(path); statErr == nil && info.IsDir() {
+		path = filepath.Join(path, "massdriver.yaml")
+	}
+	cmd.SilenceUsage = true
+
+	built, err := resourcetype.Build(path)
+	if err != nil {
+		return err
+	}
+
+	jsonBytes, err := json.MarshalIndent(built, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal resource type to JSON: %w", err)
+	}
+	fmt.Println(string(jsonBytes))
+	return nil
+}
+
+func runTypeRenderExport(cmd *cobra.Command, args []string) error {
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return err
+	}
+	dataPath, err := cmd.Flags().GetString("data")
+	if err != nil {
+		return err
+	}
+	strict, err := cmd.Flags().GetBool("strict")
+	if err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+
+	path := args[0]
+	if info, statErr := os.Stat(path); statErr == nil && info.IsDir() {
+		path = filepath.Join(path, "massdriver.yaml")
+	}
+
+	data := map[string]any{}
+	if readErr := files.Read(dataPath, &data); readErr != nil {
+		return fmt.Errorf("failed to read resource data from %s: %w", dataPath, readErr)
+	}
+
+	rendered, err := resourcetype.RenderExport(path, resourcetype.RenderExportOptions{
+		Format: format,
+		Data:   data,
+		Strict: strict,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Print(rendered)
+	return nil
+}
+
 func runTypePublish(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
 	defFile := args[0]
+	checkCompat, err := cmd.Flags().GetBool("check-compat")
+	if err != nil {
+		return err
+	}
+	oci, err := cmd.Flags().GetBool("oci")
+	if err != nil {
+		return err
+	}
 	cmd.SilenceUsage = true
 
 	mdClient, err := massdriver.NewClient()
@@ -125,6 +276,16 @@ func runTypePublish(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("error initializing massdriver client: %w", err)
 	}
 
+	if checkCompat {
+		if compatErr := checkTypeCompat(ctx, mdClient, defFile); compatErr != nil {
+			return compatErr
+		}
+	}
+
+	if oci {
+		return publishTypeOCI(ctx, mdClient, defFile)
+	}
+
 	artDef, publishErr := resourcetype.Publish(ctx, mdClient, defFile)
 	if publishErr != nil {
 		return fmt.Errorf("error publishing resource type: %w", publishErr)
@@ -135,6 +296,124 @@ func runTypePublish(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// publishTypeOCI publishes the resource type directory of the massdriver.yaml at path
+// to the registry.
+func publishTypeOCI(ctx context.Context, mdClient *massdriver.Client, path string) error {
+	if info, statErr := os.Stat(path); statErr == nil && info.IsDir() {
+		path = filepath.Join(path, "massdriver.yaml")
+	}
+
+	cfg := mdClient.Config()
+	fmt.Printf("Publishing %s to organization %s...\n", prettylogs.Underline(path), prettylogs.Underline(cfg.OrganizationID))
+	version, descriptor, publishErr := resourcetype.PublishOCI(ctx, mdClient, path)
+	if publishErr != nil {
+		return fmt.Errorf("error publishing resource type: %w", publishErr)
+	}
+
+	fmt.Printf("Resource type version %s published successfully! (Digest: %s)\n", prettylogs.Underline(version), descriptor.Digest)
+	return nil
+}
+
+func runTypePull(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	name, version, err := resourcetype.ParseReference(args[0])
+	if err != nil {
+		return err
+	}
+	directory, err := cmd.Flags().GetString("directory")
+	if err != nil {
+		return err
+	}
+	if directory == "" {
+		directory = name[strings.LastIndex(name, "/")+1:]
+	}
+	force, err := cmd.Flags().GetBool("force")
+	if err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+
+	mdYamlPath := filepath.Join(directory, "massdriver.yaml")
+	if _, statErr := os.Stat(mdYamlPath); statErr == nil && !force {
+		fmt.Printf("Resource type already exists at %s. Continuing will overwrite its contents. Continue? (y/N): ", mdYamlPath)
+		reader := bufio.NewReader(os.Stdin)
+		answer, _ := reader.ReadString('\n')
+		answer = strings.TrimSpace(strings.ToLower(answer))
+		if answer != "y" && answer != "yes" {
+			fmt.Println("Resource type pull aborted!")
+			return nil
+		}
+	}
+
+	mdClient, err := massdriver.NewClient()
+	if err != nil {
+		return fmt.Errorf("error initializing massdriver client: %w", err)
+	}
+
+	tag, descriptor, pullErr := resourcetype.Pull(ctx, mdClient, name, version, directory)
+	if pullErr != nil {
+		return fmt.Errorf("error pulling resource type: %w", pullErr)
+	}
+
+	fmt.Printf("Resource type %s@%s pulled to %s (Digest: %s)\n", prettylogs.Underline(name), prettylogs.Underline(tag), directory, descriptor.Digest)
+	return nil
+}
+
+// checkTypeCompat fails when existing resources of the resource type at path don't
+// validate against its schema, listing each one.
+func checkTypeCompat(ctx context.Context, mdClient *massdriver.Client, path string) error {
+	rt, readErr := resourcetype.Read(ctx, mdClient, path)
+	if readErr != nil {
+		return fmt.Errorf("failed to read resource type: %w", readErr)
+	}
+	name, nameErr := resourcetype.Name(rt)
+	if nameErr != nil {
+		return nameErr
+	}
+
+	incompatible, compatErr := resourcetype.CheckCompat(rt, mdClient.Resources.Iter(ctx, resources.ListInput{ResourceType: name}))
+	if compatErr != nil {
+		return compatErr
+	}
+	if len(incompatible) == 0 {
+		fmt.Printf("%s Existing %s resources validate against the new schema\n", prettylogs.Green("✓"), name)
+		return nil
+	}
+
+	for _, inc := range incompatible {
+		fmt.Printf("%s %s (%s)\n", prettylogs.Red("✗"), inc.ResourceName, inc.ResourceID)
+		for _, message := range inc.Messages {
+			fmt.Printf("    %s\n", message)
+		}
+	}
+	return fmt.Errorf("%d existing resource(s) of type %s don't validate against the new schema, not publishing", len(incompatible), name)
+}
+
+func runTypeDiff(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	name := args[0]
+	path := args[1]
+	cmd.SilenceUsage = true
+
+	mdClient, err := massdriver.NewClient()
+	if err != nil {
+		return fmt.Errorf("error initializing massdriver client: %w", err)
+	}
+
+	diff, diffErr := resourcetype.Diff(ctx, mdClient, name, path)
+	if diffErr != nil {
+		return diffErr
+	}
+	if diff == "" {
+		fmt.Printf("Resource type %s matches %s\n", prettylogs.Underline(name), path)
+		return nil
+	}
+	fmt.Print(prettylogs.Diff(diff))
+	return nil
+}
+
 func runTypeList(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
@@ -1,13 +1,16 @@
 package cmd
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 
 	"github.com/massdriver-cloud/mass/docs/helpdocs"
+	"github.com/massdriver-cloud/mass/internal/files"
 	"github.com/massdriver-cloud/mass/internal/jsonschema"
 	"github.com/massdriver-cloud/mass/internal/prettylogs"
 	"github.com/massdriver-cloud/mass/internal/resourcetype"
@@ -43,7 +46,40 @@ func NewCmdSchema() *cobra.Command {
 	_ = schemaValidateCmd.MarkFlagRequired("document")
 	_ = schemaValidateCmd.MarkFlagRequired("schema")
 
+	schemaInferCmd := &cobra.Command{
+		Use:   "infer <example-files...>",
+		Short: "Generates a JSON Schema from example documents",
+		Long:  helpdocs.MustRender("schema/infer"),
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  runSchemaInfer,
+	}
+	schemaInferCmd.Flags().StringP("output", "o", "json", "Output format (json, yaml)")
+
+	schemaConvertCmd := &cobra.Command{
+		Use:   "convert <file>",
+		Short: "Converts a JSON Schema between JSON and YAML or between drafts",
+		Long:  helpdocs.MustRender("schema/convert"),
+		Args:  cobra.ExactArgs(1),
+		RunE:  runSchemaConvert,
+	}
+	schemaConvertCmd.Flags().StringP("output", "o", "", "Output format (json, yaml), defaults to the format of the file")
+	schemaConvertCmd.Flags().String("draft", "", "JSON Schema draft to convert to (draft-07, 2020-12)")
+
+	schemaFmtCmd := &cobra.Command{
+		Use:   "fmt <files...>",
+		Short: "Formats JSON Schemas with canonical keyword ordering",
+		Long:  helpdocs.MustRender("schema/fmt"),
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  runSchemaFmt,
+	}
+	schemaFmtCmd.Flags().BoolP("write", "w", false, "Write the formatted schemas back to their files")
+	schemaFmtCmd.Flags().Bool("check", false, "List schemas that aren't formatted and fail if there are any")
+	schemaFmtCmd.MarkFlagsMutuallyExclusive("write", "check")
+
+	schemaCmd.AddCommand(schemaConvertCmd)
 	schemaCmd.AddCommand(schemaDereferenceCmd)
+	schemaCmd.AddCommand(schemaFmtCmd)
+	schemaCmd.AddCommand(schemaInferCmd)
 	schemaCmd.AddCommand(schemaValidateCmd)
 
 	return schemaCmd
@@ -119,3 +155,123 @@ func runSchemaValidate(cmd *cobra.Command, args []string) error {
 	fmt.Println(greenCheckmark, "The document is valid against the schema!")
 	return nil
 }
+
+func runSchemaInfer(cmd *cobra.Command, args []string) error {
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return err
+	}
+	if output != "json" && output != "yaml" {
+		return fmt.Errorf("unsupported output format %q, must be json or yaml", output)
+	}
+	cmd.SilenceUsage = true
+
+	examples := make([]any, 0, len(args))
+	for _, path := range args {
+		var example any
+		if readErr := files.Read(path, &example); readErr != nil {
+			return fmt.Errorf("failed to read example %s: %w", path, readErr)
+		}
+		examples = append(examples, example)
+	}
+
+	inferred, marshalErr := json.Marshal(jsonschema.Infer(examples...))
+	if marshalErr != nil {
+		return fmt.Errorf("failed to marshal inferred schema: %w", marshalErr)
+	}
+	formatted, formatErr := jsonschema.Format(inferred, ".json", "."+output)
+	if formatErr != nil {
+		return fmt.Errorf("failed to format inferred schema: %w", formatErr)
+	}
+
+	fmt.Fprint(cmd.OutOrStdout(), string(formatted))
+	return nil
+}
+
+func runSchemaConvert(cmd *cobra.Command, args []string) error {
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return err
+	}
+	draft, err := cmd.Flags().GetString("draft")
+	if err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+
+	schemaPath := args[0]
+	from := filepath.Ext(schemaPath)
+	if from != ".json" && from != ".yaml" && from != ".yml" {
+		return fmt.Errorf("unsupported file type %s, must be .json or .yaml", from)
+	}
+	to := from
+	if output != "" {
+		if output != "json" && output != "yaml" {
+			return fmt.Errorf("unsupported output format %q, must be json or yaml", output)
+		}
+		to = "." + output
+	}
+
+	data, readErr := os.ReadFile(schemaPath)
+	if readErr != nil {
+		return fmt.Errorf("failed to read schema %s: %w", schemaPath, readErr)
+	}
+	converted, convertErr := jsonschema.Convert(data, from, to, jsonschema.Draft(draft))
+	if convertErr != nil {
+		return fmt.Errorf("failed to convert schema %s: %w", schemaPath, convertErr)
+	}
+
+	fmt.Fprint(cmd.OutOrStdout(), string(converted))
+	return nil
+}
+
+func runSchemaFmt(cmd *cobra.Command, args []string) error {
+	write, err := cmd.Flags().GetBool("write")
+	if err != nil {
+		return err
+	}
+	check, err := cmd.Flags().GetBool("check")
+	if err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+
+	unformatted := []string{}
+	for _, schemaPath := range args {
+		ext := filepath.Ext(schemaPath)
+		if !slices.Contains([]string{".json", ".yaml", ".yml"}, ext) {
+			return fmt.Errorf("unsupported file type %s for %s, must be .json or .yaml", ext, schemaPath)
+		}
+		data, readErr := os.ReadFile(schemaPath)
+		if readErr != nil {
+			return fmt.Errorf("failed to read schema %s: %w", schemaPath, readErr)
+		}
+		formatted, formatErr := jsonschema.Format(data, ext, ext)
+		if formatErr != nil {
+			return fmt.Errorf("failed to format schema %s: %w", schemaPath, formatErr)
+		}
+
+		switch {
+		case check:
+			if !bytes.Equal(data, formatted) {
+				unformatted = append(unformatted, schemaPath)
+				fmt.Fprintln(cmd.OutOrStdout(), schemaPath)
+			}
+		case write:
+			if bytes.Equal(data, formatted) {
+				continue
+			}
+			if writeErr := os.WriteFile(schemaPath, formatted, files.UserRW); writeErr != nil {
+				return fmt.Errorf("failed to write schema %s: %w", schemaPath, writeErr)
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), schemaPath)
+		default:
+			fmt.Fprint(cmd.OutOrStdout(), string(formatted))
+		}
+	}
+
+	if len(unformatted) > 0 {
+		return fmt.Errorf("%d schema file(s) aren't formatted, run mass schema fmt -w to fix them", len(unformatted))
+	}
+	return nil
+}
@@ -1,7 +1,9 @@
 // Package api is a temporary holding pen for GraphQL operations that the
-// massdriver-sdk-go doesn't expose yet. Today this is just the resource-type
-// surface (Get / List / Publish / Delete). When the SDK grows native support
-// the corresponding files here disappear; once the package is empty, delete it.
+// massdriver-sdk-go doesn't expose yet. Today this is the resource-type surface
+// (Get / List / Publish / Delete) and the resource references environments and
+// their instances hold (ListEnvironmentResourceRefs). When the SDK grows native
+// support the corresponding files here disappear; once the package is empty,
+// delete it.
 package api
 
 import (
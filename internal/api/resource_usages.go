@@ -0,0 +1,131 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Khan/genqlient/graphql"
+	"github.com/massdriver-cloud/massdriver-sdk-go/massdriver"
+	"github.com/massdriver-cloud/massdriver-sdk-go/massdriver/gql/scalars"
+)
+
+// EnvironmentResourceRefs is an environment with every reference it and its
+// instances hold to resources: the environment's defaults, and each instance's
+// connections and remote references.
+type EnvironmentResourceRefs struct {
+	ID        string                 `json:"id"`
+	Name      string                 `json:"name"`
+	Project   *ProjectRef            `json:"project,omitempty"`
+	Defaults  []ResourceRef          `json:"defaults"`
+	Instances []InstanceResourceRefs `json:"instances"`
+}
+
+// ProjectRef identifies the project an environment belongs to.
+type ProjectRef struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// InstanceResourceRefs is an instance's connections and remote references.
+type InstanceResourceRefs struct {
+	ID               string     `json:"id"`
+	Name             string     `json:"name"`
+	Connections      []FieldRef `json:"connections"`
+	RemoteReferences []FieldRef `json:"remoteReferences"`
+}
+
+// FieldRef is a resource an instance references from one of its fields.
+type FieldRef struct {
+	Field    string       `json:"field"`
+	Resource *ResourceRef `json:"resource"`
+}
+
+// ResourceRef identifies a referenced resource.
+type ResourceRef struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// environmentsPageSize mirrors resourceTypesPageSize; see ListResourceTypes.
+const environmentsPageSize = 100
+
+const listEnvironmentResourceRefsQuery = `query listEnvironmentResourceRefs($organizationId: ID!, $cursor: Cursor) {
+  environments(organizationId: $organizationId, cursor: $cursor) {
+    items {
+      id
+      name
+      project {
+        id
+        name
+      }
+      defaults {
+        id
+        name
+      }
+      instances {
+        id
+        name
+        connections {
+          field
+          resource {
+            id
+            name
+          }
+        }
+        remoteReferences {
+          field
+          resource {
+            id
+            name
+          }
+        }
+      }
+    }
+    cursor {
+      next
+      previous
+    }
+  }
+}`
+
+// ListEnvironmentResourceRefs fetches every environment in the configured
+// organization with the resources it and its instances reference. The SDK's
+// environment and instance models don't carry connections or remote references
+// yet, so this walks the pages by hand like ListResourceTypes.
+func ListEnvironmentResourceRefs(ctx context.Context, mdClient *massdriver.Client) ([]EnvironmentResourceRefs, error) {
+	cfg := mdClient.Config()
+	client := gqlClient(mdClient)
+
+	var all []EnvironmentResourceRefs
+	after := ""
+	for {
+		var resp struct {
+			Environments struct {
+				Items  []EnvironmentResourceRefs `json:"items"`
+				Cursor struct {
+					Next     string `json:"next"`
+					Previous string `json:"previous"`
+				} `json:"cursor"`
+			} `json:"environments"`
+		}
+		req := &graphql.Request{
+			OpName: "listEnvironmentResourceRefs",
+			Query:  listEnvironmentResourceRefsQuery,
+			Variables: map[string]any{
+				"organizationId": cfg.OrganizationID,
+				"cursor":         scalars.NewCursor(environmentsPageSize, after),
+			},
+		}
+		if err := client.MakeRequest(ctx, req, &graphql.Response{Data: &resp}); err != nil {
+			return nil, fmt.Errorf("list environment resource references: %w", err)
+		}
+		all = append(all, resp.Environments.Items...)
+
+		next := resp.Environments.Cursor.Next
+		if next == "" || next == after {
+			break
+		}
+		after = next
+	}
+	return all, nil
+}
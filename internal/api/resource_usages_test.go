@@ -0,0 +1,135 @@
+package api_test
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Khan/genqlient/graphql"
+	"github.com/massdriver-cloud/mass/internal/api"
+	"github.com/massdriver-cloud/massdriver-sdk-go/massdriver"
+	"github.com/massdriver-cloud/massdriver-sdk-go/massdriver/gql/gqltest"
+)
+
+// queryRecorder passes requests through to a gqltest client, keeping each query so
+// tests can pin the document sent to the server.
+type queryRecorder struct {
+	graphql.Client
+	queries []string
+}
+
+func (r *queryRecorder) MakeRequest(ctx context.Context, req *graphql.Request, resp *graphql.Response) error {
+	r.queries = append(r.queries, req.Query)
+	return r.Client.MakeRequest(ctx, req, resp)
+}
+
+func TestListEnvironmentResourceRefs(t *testing.T) {
+	page := func(items []map[string]any, next string) map[string]any {
+		return map[string]any{
+			"environments": map[string]any{
+				"items":  items,
+				"cursor": map[string]any{"next": next, "previous": ""},
+			},
+		}
+	}
+	mock := gqltest.NewClient(
+		gqltest.RespondWithData(page([]map[string]any{
+			{
+				"id":       "env-1",
+				"name":     "prod",
+				"project":  map[string]any{"id": "proj-1", "name": "network"},
+				"defaults": []map[string]any{{"id": "vpc-id", "name": "prod-vpc"}},
+				"instances": []map[string]any{
+					{
+						"id":   "inst-1",
+						"name": "db",
+						"connections": []map[string]any{
+							{"field": "network", "resource": map[string]any{"id": "vpc-id", "name": "prod-vpc"}},
+							{"field": "bucket", "resource": nil},
+						},
+						"remoteReferences": []map[string]any{},
+					},
+				},
+			},
+		}, "cursor-2")),
+		gqltest.RespondWithData(page([]map[string]any{
+			{
+				"id":        "env-2",
+				"name":      "staging",
+				"defaults":  []map[string]any{},
+				"instances": []map[string]any{},
+			},
+		}, "")),
+	)
+	recorder := &queryRecorder{Client: mock}
+	t.Cleanup(api.SetTransportForTest(recorder))
+	mdClient, err := massdriver.NewClient(
+		massdriver.WithGQLClient(recorder),
+		massdriver.WithOrganizationID("test-org"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := api.ListEnvironmentResourceRefs(t.Context(), mdClient)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []api.EnvironmentResourceRefs{
+		{
+			ID:       "env-1",
+			Name:     "prod",
+			Project:  &api.ProjectRef{ID: "proj-1", Name: "network"},
+			Defaults: []api.ResourceRef{{ID: "vpc-id", Name: "prod-vpc"}},
+			Instances: []api.InstanceResourceRefs{
+				{
+					ID:   "inst-1",
+					Name: "db",
+					Connections: []api.FieldRef{
+						{Field: "network", Resource: &api.ResourceRef{ID: "vpc-id", Name: "prod-vpc"}},
+						{Field: "bucket"},
+					},
+					RemoteReferences: []api.FieldRef{},
+				},
+			},
+		},
+		{
+			ID:        "env-2",
+			Name:      "staging",
+			Defaults:  []api.ResourceRef{},
+			Instances: []api.InstanceResourceRefs{},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	// the query is hand-written, so pin it against the fields decoded above
+	wantQuery, err := os.ReadFile("testdata/list-environment-resource-refs.graphql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recorder.queries) != 2 {
+		t.Fatalf("got %d requests, want 2 (should follow cursor.next)", len(recorder.queries))
+	}
+	for _, query := range recorder.queries {
+		if query != strings.TrimSuffix(string(wantQuery), "\n") {
+			t.Errorf("got query:\n%s\nwant:\n%s", query, wantQuery)
+		}
+	}
+
+	reqs := mock.Requests()
+	if reqs[0].OpName != "listEnvironmentResourceRefs" || reqs[0].Variables["organizationId"] != "test-org" {
+		t.Errorf("got request %+v, want listEnvironmentResourceRefs for test-org", reqs[0])
+	}
+	cursor2, ok := reqs[1].Variables["cursor"].(map[string]any)
+	if !ok || cursor2["next"] != "cursor-2" {
+		t.Errorf("second request should carry next=cursor-2, got %v", reqs[1].Variables["cursor"])
+	}
+	if pending := mock.Pending(); pending != 0 {
+		t.Errorf("expected all queued responses consumed, %d pending", pending)
+	}
+}
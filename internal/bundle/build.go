@@ -7,10 +7,29 @@ import (
 	"github.com/massdriver-cloud/mass/internal/provisioners"
 )
 
+// BuildOptions controls the optional files generated by BuildWithOptions.
+type BuildOptions struct {
+	// GenerateOutputs scaffolds output declarations for the bundle's artifacts in
+	// steps whose provisioner supports it, if they don't already exist.
+	GenerateOutputs bool
+	// RefPath is the bundle path relative file $refs are resolved from, for building a
+	// copy of a bundle. Defaults to buildPath.
+	RefPath string
+}
+
 // Build dereferences schemas (using resolver for massdriver $refs), writes
 // them to disk, and exports provisioner inputs for all steps.
 func (b *Bundle) Build(buildPath string, resolver SchemaResolver) error {
-	err := b.DereferenceSchemas(buildPath, resolver)
+	return b.BuildWithOptions(buildPath, resolver, BuildOptions{})
+}
+
+// BuildWithOptions is Build with control over the optional generated files.
+func (b *Bundle) BuildWithOptions(buildPath string, resolver SchemaResolver, opts BuildOptions) error {
+	refPath := opts.RefPath
+	if refPath == "" {
+		refPath = buildPath
+	}
+	err := b.DereferenceSchemas(refPath, resolver)
 	if err != nil {
 		return err
 	}
@@ -20,10 +39,21 @@ func (b *Bundle) Build(buildPath string, resolver SchemaResolver) error {
 		return err
 	}
 
+	exportOpts := provisioners.ExportOptions{Connections: b.Connections}
+	if opts.GenerateOutputs {
+		exportOpts.Artifacts = b.Artifacts
+	}
+
 	combined := b.CombineParamsConnsMetadata()
 	for _, step := range b.Steps {
+		stepPath := filepath.Join(buildPath, step.Path)
+		variables := b.stepVariables(step, combined)
 		prov := provisioners.NewProvisioner(step.Provisioner)
-		err = prov.ExportMassdriverInputs(filepath.Join(buildPath, step.Path), combined)
+		if exporter, ok := prov.(provisioners.OptionsExporter); ok {
+			err = exporter.ExportMassdriverInputsWithOptions(stepPath, variables, exportOpts)
+		} else {
+			err = prov.ExportMassdriverInputs(stepPath, variables)
+		}
 		if err != nil {
 			return err
 		}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"path"
+	"strings"
 	"testing"
 
 	"github.com/massdriver-cloud/mass/internal/bundle"
@@ -244,6 +245,30 @@ func TestBuildSchemas(t *testing.T) {
 	}
 }
 
+func TestBuildFetchesResourceTypesOnce(t *testing.T) {
+	testDir := t.TempDir()
+	if err := mockfilesystem.SetupBundle(testDir); err != nil {
+		t.Fatal(err)
+	}
+	b, err := bundle.Unmarshal(testDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the artifact and the connection both $ref massdriver/draft-node
+	calls := 0
+	resolver := func(_ context.Context, _ string) (map[string]any, error) {
+		calls++
+		return draftNodeSchema, nil
+	}
+	if err = b.Build(testDir, resolver); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Errorf("got %d resolver calls, want 1", calls)
+	}
+}
+
 func TestBuildTFVars(t *testing.T) {
 	testDir := t.TempDir()
 	if err := mockfilesystem.SetupBundle(testDir); err != nil {
@@ -274,3 +299,119 @@ func TestBuildTFVars(t *testing.T) {
 		}
 	}
 }
+
+func TestBuildInputsFrom(t *testing.T) {
+	testDir := t.TempDir()
+	for _, dir := range []string{"network", "app"} {
+		if err := os.MkdirAll(path.Join(testDir, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(path.Join(testDir, "app", "variables.tf"), []byte("variable \"md_metadata\" {\n  type = any\n}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	b := &bundle.Bundle{
+		Name: "wired",
+		Steps: []bundle.Step{
+			{Path: "network", Provisioner: "opentofu"},
+			{Path: "app", Provisioner: "opentofu", InputsFrom: []bundle.StepInput{{Step: "network", Output: "cluster_endpoint"}}},
+		},
+		Params:      map[string]any{"properties": map[string]any{}},
+		Connections: map[string]any{"properties": map[string]any{}},
+		Artifacts:   map[string]any{"properties": map[string]any{}},
+		UI:          map[string]any{},
+	}
+
+	if err := b.Build(testDir, stubResolver(draftNodeSchema)); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `// This file is auto-generated by massdriver from your massdriver.yaml file.
+// Any changes made directly to this file will be overwritten on the next build.
+// To opt a variable out of regeneration, move it to another file (e.g. variables.tf).
+variable "cluster_endpoint" {
+  type = any
+}
+`
+	got, err := os.ReadFile(path.Join(testDir, "app", "_massdriver_variables.tf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != want {
+		t.Errorf("Expected file content to be %s but got %s", want, string(got))
+	}
+
+	networkVars, err := os.ReadFile(path.Join(testDir, "network", "_massdriver_variables.tf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(networkVars), "cluster_endpoint") {
+		t.Errorf("Expected step network not to receive wired input, got %s", string(networkVars))
+	}
+}
+
+func TestBuildStepInputs(t *testing.T) {
+	testDir := t.TempDir()
+	for _, dir := range []string{"network", "app"} {
+		if err := os.MkdirAll(path.Join(testDir, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	b := &bundle.Bundle{
+		Name: "scoped",
+		Steps: []bundle.Step{
+			{Path: "network", Provisioner: "opentofu", Inputs: []string{"cidr"}},
+			{Path: "app", Provisioner: "opentofu", Inputs: []string{"/params/image", "/connections"}},
+		},
+		Params: map[string]any{
+			"required": []any{"cidr", "image"},
+			"properties": map[string]any{
+				"cidr":  map[string]any{"type": "string"},
+				"image": map[string]any{"type": "string"},
+			},
+		},
+		Connections: map[string]any{
+			"required": []any{"cluster"},
+			"properties": map[string]any{
+				"cluster": map[string]any{"type": "string"},
+			},
+		},
+		Artifacts: map[string]any{"properties": map[string]any{}},
+		UI:        map[string]any{},
+	}
+
+	if err := b.Build(testDir, stubResolver(draftNodeSchema)); err != nil {
+		t.Fatal(err)
+	}
+
+	type test struct {
+		step    string
+		want    []string
+		notWant []string
+	}
+	tests := []test{
+		{step: "network", want: []string{`"cidr"`, `"md_metadata"`}, notWant: []string{`"image"`, `"cluster"`}},
+		{step: "app", want: []string{`"image"`, `"cluster"`, `"md_metadata"`}, notWant: []string{`"cidr"`}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.step, func(t *testing.T) {
+			got, err := os.ReadFile(path.Join(testDir, tc.step, "_massdriver_variables.tf"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, name := range tc.want {
+				if !strings.Contains(string(got), "variable "+name) {
+					t.Errorf("Expected variable %s in step %s, got %s", name, tc.step, string(got))
+				}
+			}
+			for _, name := range tc.notWant {
+				if strings.Contains(string(got), "variable "+name) {
+					t.Errorf("Expected no variable %s in step %s, got %s", name, tc.step, string(got))
+				}
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"embed"
 	"encoding/json"
 	"fmt"
+	"os"
 	"path/filepath"
 	"regexp"
 
@@ -31,6 +32,8 @@ type Step struct {
 	Provisioner  string         `json:"provisioner,omitempty" yaml:"provisioner,omitempty" mapstructure:"provisioner"`
 	SkipOnDelete bool           `json:"skip_on_delete,omitempty" yaml:"skip_on_delete,omitempty" mapstructure:"skip_on_delete"`
 	Config       map[string]any `json:"config,omitempty" yaml:"config,omitempty" mapstructure:"config"`
+	Inputs       []string       `json:"inputs,omitempty" yaml:"inputs,omitempty" mapstructure:"inputs"`
+	InputsFrom   []StepInput    `json:"inputs_from,omitempty" yaml:"inputs_from,omitempty" mapstructure:"inputs_from"`
 }
 
 // Bundle represents a Massdriver bundle definition parsed from massdriver.yaml.
@@ -47,6 +50,12 @@ type Bundle struct {
 	Connections map[string]any `json:"connections,omitempty" yaml:"connections,omitempty" mapstructure:"connections"`
 	UI          map[string]any `json:"ui,omitempty" yaml:"ui,omitempty" mapstructure:"ui"`
 	AppSpec     *AppSpec       `json:"app,omitempty" yaml:"app,omitempty" mapstructure:"app"`
+
+	// unknownStepFields holds the unrecognized keys of each step in massdriver.yaml, as
+	// found by Unmarshal, so lint can report them.
+	unknownStepFields [][]string
+	// refFiles holds the local files reached through $refs by DereferenceSchemas.
+	refFiles []string
 }
 
 // AppSpec defines the application-specific configuration for environment variables, policies, and secrets.
@@ -71,14 +80,20 @@ func Unmarshal(readDirectory string) (*Bundle, error) {
 		return nil, err
 	}
 
+	unknownStepFields, err := readUnknownStepFields(filepath.Join(readDirectory, "massdriver.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	unmarshalledBundle.unknownStepFields = unknownStepFields
+
 	if unmarshalledBundle.Access != "" {
-		fmt.Println(prettylogs.Orange("Warning: the 'access' field in massdriver.yaml is deprecated and should be removed."))
+		fmt.Fprintln(os.Stderr, prettylogs.Orange("Warning: the 'access' field in massdriver.yaml is deprecated and should be removed."))
 	}
 	if unmarshalledBundle.Type != "" {
-		fmt.Println(prettylogs.Orange("Warning: the 'type' field in massdriver.yaml is deprecated and should be removed."))
+		fmt.Fprintln(os.Stderr, prettylogs.Orange("Warning: the 'type' field in massdriver.yaml is deprecated and should be removed."))
 	}
 	if unmarshalledBundle.Version == "" {
-		fmt.Println(prettylogs.Orange("Warning: the 'version' field in massdriver.yaml is empty. This disables all versioning capabilities."))
+		fmt.Fprintln(os.Stderr, prettylogs.Orange("Warning: the 'version' field in massdriver.yaml is empty. This disables all versioning capabilities."))
 		unmarshalledBundle.Version = "0.0.0"
 	} else if !validSemverRegex.MatchString(unmarshalledBundle.Version) {
 		return nil, fmt.Errorf("invalid version in massdriver.yaml: %s. Version must follow semantic versioning (MAJOR.MINOR.PATCH), e.g., 1.2.3", unmarshalledBundle.Version)
@@ -111,6 +126,11 @@ func Unmarshal(readDirectory string) (*Bundle, error) {
 	return unmarshalledBundle, nil
 }
 
+// IsValidVersion reports whether version is a MAJOR.MINOR.PATCH bundle version.
+func IsValidVersion(version string) bool {
+	return validSemverRegex.MatchString(version)
+}
+
 func applyAppBlockDefaults(b *Bundle) {
 	if b.AppSpec != nil {
 		if b.AppSpec.Envs == nil {
@@ -131,7 +151,7 @@ func applyStepDefaults(b *Bundle) {
 steps:
   - path: src
     provisioner: terraform`, prettylogs.Orange("Warning"))
-		fmt.Println(msg + "\n")
+		fmt.Fprintln(os.Stderr, msg+"\n")
 		b.Steps = append(b.Steps, Step{Path: "src", Provisioner: "terraform"})
 	}
 }
@@ -2,8 +2,10 @@ package bundle
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"path/filepath"
+	"slices"
 
 	"github.com/massdriver-cloud/mass/internal/resourcetype"
 )
@@ -18,7 +20,7 @@ type SchemaResolver func(ctx context.Context, name string) (map[string]any, erro
 // [resourcetype.NewMassdriverResolver] in production, or a hand-rolled stub in
 // tests.
 func (b *Bundle) DereferenceSchemas(path string, resolver SchemaResolver) error {
-	cwd := filepath.Dir(path)
+	cwd := refDir(path)
 
 	// The stripID is a hack to get around the issue of the UI choking if the params schema has 2 or more of the same $id in it.
 	// We need the "$id" in artifacts and connections, but we need to strip it out of params and ui schemas, hence the conditional.
@@ -34,6 +36,15 @@ func (b *Bundle) DereferenceSchemas(path string, resolver SchemaResolver) error
 		{schema: &b.UI, label: "ui", stripID: true},
 	}
 
+	// one cache for all the schemas, since connections and artifacts often $ref the same resource types
+	cache := resourcetype.NewRefCache()
+	b.refFiles = nil
+	onFile := func(path string) {
+		if !slices.Contains(b.refFiles, path) {
+			b.refFiles = append(b.refFiles, path)
+		}
+	}
+
 	for _, task := range tasks {
 		if task.schema == nil {
 			*task.schema = map[string]any{
@@ -41,7 +52,7 @@ func (b *Bundle) DereferenceSchemas(path string, resolver SchemaResolver) error
 			}
 		}
 
-		dereferencedSchema, err := resourcetype.DereferenceSchema(*task.schema, resourcetype.DereferenceOptions{Resolver: resolver, Cwd: cwd, StripID: task.stripID})
+		dereferencedSchema, err := resourcetype.DereferenceSchema(*task.schema, resourcetype.DereferenceOptions{Resolver: resolver, Cwd: cwd, StripID: task.stripID, OnFile: onFile, Cache: cache})
 
 		if err != nil {
 			return err
@@ -57,3 +68,32 @@ func (b *Bundle) DereferenceSchemas(path string, resolver SchemaResolver) error
 
 	return nil
 }
+
+// DereferenceParams returns the bundle's params schema with its $refs resolved the same
+// way DereferenceSchemas resolves them, without dereferencing the other schemas.
+func (b *Bundle) DereferenceParams(path string, resolver SchemaResolver) (map[string]any, error) {
+	params := b.Params
+	if params == nil {
+		params = map[string]any{"properties": map[string]any{}}
+	}
+	dereferenced, err := resourcetype.DereferenceSchema(params, resourcetype.DereferenceOptions{Resolver: resolver, Cwd: refDir(path), StripID: true})
+	if err != nil {
+		return nil, err
+	}
+	sch, ok := dereferenced.(map[string]any)
+	if !ok {
+		return nil, errors.New("hydrated params is not a map")
+	}
+	return sch, nil
+}
+
+// refDir is the directory relative file $refs in the bundle at path are resolved from.
+func refDir(path string) string {
+	return filepath.Dir(path)
+}
+
+// RefFiles returns the absolute paths of the local files reached through $refs during
+// the last call to DereferenceSchemas.
+func (b *Bundle) RefFiles() []string {
+	return slices.Clone(b.refFiles)
+}
@@ -97,3 +97,40 @@ func TestNilResult(t *testing.T) {
 		t.Errorf("Wanted %s but got %s", want, asJSON)
 	}
 }
+
+func TestNonStringResults(t *testing.T) {
+	input := map[string]any{
+		"params": map[string]any{
+			"port":    float64(5432),
+			"ratio":   0.25,
+			"enabled": true,
+			"hosts":   []any{"a.example.com", "b.example.com"},
+			"labels":  map[string]any{"team": "data"},
+		},
+	}
+	query := map[string]string{
+		"PORT":    ".params.port",
+		"RATIO":   ".params.ratio",
+		"ENABLED": ".params.enabled",
+		"HOSTS":   ".params.hosts",
+		"LABELS":  ".params.labels",
+	}
+
+	got := bundle.ParseEnvironmentVariables(input, query)
+
+	want := map[string]string{
+		"PORT":    "5432.000000",
+		"RATIO":   "0.250000",
+		"ENABLED": "true",
+		"HOSTS":   `["a.example.com","b.example.com"]`,
+		"LABELS":  `{"team":"data"}`,
+	}
+	for name, value := range want {
+		if got[name].Error != "" {
+			t.Errorf("%s: unexpected error %s", name, got[name].Error)
+		}
+		if got[name].Value != value {
+			t.Errorf("%s: wanted %s but got %s", name, value, got[name].Value)
+		}
+	}
+}
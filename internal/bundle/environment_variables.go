@@ -1,6 +1,7 @@
 package bundle
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
 
@@ -16,6 +17,7 @@ type ParsedEnvironmentVariable struct {
 const nonStringReturnErrorMessage = "failed to return value of type string"
 
 // ParseEnvironmentVariables evaluates each jq query against params and returns the results keyed by env var name.
+// Scalars are converted to strings and objects and arrays are encoded as JSON.
 func ParseEnvironmentVariables(params map[string]any, query map[string]string) map[string]ParsedEnvironmentVariable {
 	results := make(map[string]ParsedEnvironmentVariable)
 
@@ -62,8 +64,15 @@ func ParseEnvironmentVariables(params map[string]any, query map[string]string) m
 				castValue = fmt.Sprintf("%s", v)
 			case reflect.Bool:
 				castValue = fmt.Sprintf("%t", v)
+			case reflect.Map, reflect.Slice:
+				// objects and arrays are passed to the app as JSON
+				encoded, marshalErr := json.Marshal(v)
+				if marshalErr != nil {
+					result.Error = fmt.Sprint(marshalErr)
+				}
+				castValue = string(encoded)
 			// Lint wants an exhaustive list. Making it to appease the linter
-			case reflect.Invalid, reflect.Complex64, reflect.Complex128, reflect.Array, reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Pointer, reflect.Slice, reflect.Struct, reflect.UnsafePointer:
+			case reflect.Invalid, reflect.Complex64, reflect.Complex128, reflect.Array, reflect.Chan, reflect.Func, reflect.Interface, reflect.Pointer, reflect.Struct, reflect.UnsafePointer:
 				result.Error = nonStringReturnErrorMessage
 				results[k] = result
 			default:
@@ -233,13 +233,13 @@ func (b *Bundle) LintInputsMatchProvisioner() LintResult {
 	var result LintResult
 
 	massdriverInputs := b.CombineParamsConnsMetadata()
-	massdriverInputsProperties, ok := massdriverInputs["properties"].(map[string]any)
-	if !ok {
+	if _, ok := massdriverInputs["properties"].(map[string]any); !ok {
 		result.AddError("param-mismatch", "enabled to convert to map[string]interface")
 		return result
 	}
 
 	for _, step := range b.Steps {
+		massdriverInputsProperties, _ := b.stepInputs(step, massdriverInputs)["properties"].(map[string]any)
 		prov := provisioners.NewProvisioner(step.Provisioner)
 		provisionerInputs, err := prov.ReadProvisionerInputs(step.Path)
 		if err != nil {
@@ -263,9 +263,10 @@ func (b *Bundle) LintInputsMatchProvisioner() LintResult {
 			}
 		}
 
+		wiredInputs := step.wiredInputNames()
 		missingMassdriverInputs := []string{}
 		for name := range provisionerInputsProperties {
-			if _, exists = massdriverInputsProperties[name]; !exists {
+			if _, exists = massdriverInputsProperties[name]; !exists && !slices.Contains(wiredInputs, name) {
 				missingMassdriverInputs = append(missingMassdriverInputs, name)
 			}
 		}
@@ -287,3 +288,97 @@ func (b *Bundle) LintInputsMatchProvisioner() LintResult {
 
 	return result
 }
+
+// LintStepInputs checks that every step's inputs selection resolves to declared params or
+// connections, and that every inputs_from entry references an output declared by an earlier
+// step without shadowing a param, connection or metadata input.
+//
+//nolint:gocognit
+func (b *Bundle) LintStepInputs() LintResult {
+	var result LintResult
+
+	massdriverInputsProperties, _ := b.CombineParamsConnsMetadata()["properties"].(map[string]any)
+
+	for i, step := range b.Steps {
+		_, unresolved := b.selectedInputNames(step)
+		for _, entry := range unresolved {
+			result.AddError("step-inputs", fmt.Sprintf("step %s selects input %q, which doesn't match any param or connection", step.Path, entry))
+		}
+
+		for _, input := range step.InputsFrom {
+			if _, exists := massdriverInputsProperties[input.Output]; exists {
+				result.AddError("step-inputs", fmt.Sprintf("step %s wires output %q, which collides with a param, connection or metadata input of the same name", step.Path, input.Output))
+			}
+
+			source := slices.IndexFunc(b.Steps, func(s Step) bool { return s.Path == input.Step })
+			switch {
+			case source == -1:
+				result.AddError("step-inputs", fmt.Sprintf("step %s wires output %q from unknown step %q", step.Path, input.Output, input.Step))
+				continue
+			case source >= i:
+				result.AddError("step-inputs", fmt.Sprintf("step %s wires output %q from step %q, which does not run before it", step.Path, input.Output, input.Step))
+				continue
+			}
+
+			reader, ok := provisioners.NewProvisioner(b.Steps[source].Provisioner).(provisioners.OutputReader)
+			if !ok {
+				result.AddWarning("step-inputs", fmt.Sprintf("unable to verify output %q of step %s: provisioner %q doesn't support reading outputs", input.Output, input.Step, b.Steps[source].Provisioner))
+				continue
+			}
+			outputs, err := reader.ReadProvisionerOutputs(b.Steps[source].Path)
+			if err != nil {
+				result.AddError("step-inputs", err.Error())
+				continue
+			}
+			if !slices.Contains(outputs, input.Output) {
+				result.AddError("step-inputs", fmt.Sprintf("step %s wires output %q from step %s, but that step's IaC doesn't declare it", step.Path, input.Output, input.Step))
+			}
+		}
+	}
+
+	return result
+}
+
+// LintStepConfig reports unrecognized step fields in massdriver.yaml and validates each
+// step's config against its provisioner's config schema. Unknown step fields and config
+// keys are warnings rather than errors.
+func (b *Bundle) LintStepConfig() LintResult {
+	var result LintResult
+
+	for i, step := range b.Steps {
+		// warnings, like unknown config keys, since the platform may accept fields this CLI doesn't know yet
+		if i < len(b.unknownStepFields) {
+			for _, field := range b.unknownStepFields[i] {
+				msg := fmt.Sprintf("step %s has unknown field %q", step.Path, field)
+				if suggestion := closestStepField(field); suggestion != "" {
+					msg += fmt.Sprintf(", did you mean %q?", suggestion)
+				}
+				result.AddWarning("step-config", msg)
+			}
+		}
+
+		validator, ok := provisioners.NewProvisioner(step.Provisioner).(provisioners.ConfigValidator)
+		if !ok {
+			continue
+		}
+		if err := validator.ValidateConfig(step.Config); err != nil {
+			result.AddError("step-config", fmt.Sprintf("invalid %s config for step %s: %v", step.Provisioner, step.Path, err))
+		}
+		// the platform may read keys the schema doesn't list, so these are only warnings
+		unknown, unknownErr := validator.UnknownConfigKeys(step.Config)
+		if unknownErr != nil {
+			result.AddError("step-config", unknownErr.Error())
+			continue
+		}
+		for _, key := range unknown {
+			msg := fmt.Sprintf("step %s has unknown %s config key %q", step.Path, step.Provisioner, key.Path)
+			name := key.Path[strings.LastIndex(key.Path, ".")+1:]
+			if suggestion := closestName(name, key.Known); suggestion != "" {
+				msg += fmt.Sprintf(", did you mean %q?", suggestion)
+			}
+			result.AddWarning("step-config", msg)
+		}
+	}
+
+	return result
+}
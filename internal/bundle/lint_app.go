@@ -0,0 +1,243 @@
+package bundle
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/itchyny/gojq"
+)
+
+// envVarNamePattern is what the platform accepts as an env var or secret name.
+var envVarNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// appInputs are the top-level keys of the document app.envs and app.policies are
+// evaluated against.
+var appInputs = []string{"params", "connections", "md_metadata"}
+
+// LintAppSpec checks the app block: env names and jq queries, policy paths and secret
+// names and descriptions. Queries may only reference declared params, connections and
+// metadata, and policies must point into a declared connection. Run it on a
+// dereferenced bundle so paths can be checked against connection schemas.
+func (b *Bundle) LintAppSpec() LintResult {
+	var result LintResult
+	if b.AppSpec == nil {
+		return result
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(b.AppSpec.Envs)) {
+		if !envVarNamePattern.MatchString(name) {
+			result.AddError("app-envs", fmt.Sprintf("env %s is not a valid environment variable name, use letters, digits and underscores", name))
+		}
+		query, err := gojq.Parse(b.AppSpec.Envs[name])
+		if err != nil {
+			result.AddError("app-envs", fmt.Sprintf("env %s has an invalid jq query: %v", name, err))
+			continue
+		}
+		for _, path := range jqRootPaths(query) {
+			if problem := b.appInputPathProblem(path); problem != "" {
+				result.AddError("app-envs", fmt.Sprintf("env %s %s", name, problem))
+			}
+		}
+	}
+
+	for _, policy := range b.AppSpec.Policies {
+		query, err := gojq.Parse(policy)
+		if err != nil {
+			result.AddError("app-policies", fmt.Sprintf("policy %s is not a valid jq path: %v", policy, err))
+			continue
+		}
+		paths := jqRootPaths(query)
+		if len(paths) != 1 || "."+strings.Join(paths[0], ".") != policy {
+			result.AddError("app-policies", fmt.Sprintf("policy %s must be a plain path like .connections.<name>.data.security.iam.<policy>", policy))
+			continue
+		}
+		if len(paths[0]) < 2 || paths[0][0] != "connections" {
+			result.AddError("app-policies", fmt.Sprintf("policy %s must reference a connection, like .connections.<name>.data.security.iam.<policy>", policy))
+			continue
+		}
+		if problem := b.appInputPathProblem(paths[0]); problem != "" {
+			result.AddError("app-policies", fmt.Sprintf("policy %s %s", policy, problem))
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(b.AppSpec.Secrets)) {
+		secret := b.AppSpec.Secrets[name]
+		switch {
+		case !envVarNamePattern.MatchString(name):
+			result.AddError("app-secrets", fmt.Sprintf("secret %s is not a valid environment variable name, use letters, digits and underscores", name))
+		case strings.ToUpper(name) != name:
+			result.AddWarning("app-secrets", fmt.Sprintf("secret %s should be upper case, like %s, to follow environment variable naming", name, strings.ToUpper(name)))
+		}
+		if _, isEnv := b.AppSpec.Envs[name]; isEnv {
+			result.AddError("app-secrets", fmt.Sprintf("secret %s has the same name as an env, so one would overwrite the other", name))
+		}
+		if secret.JSON && strings.TrimSpace(secret.Description) == "" {
+			result.AddWarning("app-secrets", fmt.Sprintf("secret %s is JSON but has no description, describe the shape of the JSON it expects", name))
+		}
+	}
+
+	return result
+}
+
+// appInputPathProblem describes what's wrong with a path into the app input document,
+// or returns "" if it's declared or can't be checked.
+func (b *Bundle) appInputPathProblem(path []string) string {
+	reference := "." + strings.Join(path, ".")
+	if !slices.Contains(appInputs, path[0]) {
+		return fmt.Sprintf("references %s, but only .params, .connections and .md_metadata are available", reference)
+	}
+
+	var sch map[string]any
+	switch path[0] {
+	case "params":
+		sch = b.Params
+	case "connections":
+		sch = b.Connections
+	case "md_metadata":
+		metadataProps, _ := MetadataSchema["properties"].(map[string]any)
+		sch, _ = metadataProps["md_metadata"].(map[string]any)
+	}
+	if missing := undeclaredPathIndex(sch, path[1:]); missing != -1 {
+		declared := "." + strings.Join(path[:missing+2], ".")
+		return fmt.Sprintf("references %s, which isn't declared in %s", declared, path[0])
+	}
+	return ""
+}
+
+// undeclaredPathIndex follows path through the properties of sch and returns the index
+// of the first key the schema doesn't declare, or -1. Schemas that allow keys beyond
+// their properties, or whose keys can't be known statically, accept any key.
+func undeclaredPathIndex(sch map[string]any, path []string) int {
+	for i, key := range path {
+		props, hasProps := sch["properties"].(map[string]any)
+		if !hasProps {
+			return -1
+		}
+		prop, declared := props[key].(map[string]any)
+		if !declared {
+			if closedSchema(sch) {
+				return i
+			}
+			return -1
+		}
+		sch = prop
+	}
+	return -1
+}
+
+// closedSchema reports whether an object schema only allows the keys in its properties.
+func closedSchema(sch map[string]any) bool {
+	for _, keyword := range []string{"$ref", "patternProperties", "oneOf", "anyOf", "allOf", "if", "dependencies"} {
+		if _, ok := sch[keyword]; ok {
+			return false
+		}
+	}
+	if additional, ok := sch["additionalProperties"]; ok && additional != false {
+		return false
+	}
+	return true
+}
+
+// jqRootPaths returns the object key paths a jq query reads from its input, like
+// [connections postgres data] for .connections.postgres.data. Only paths applied to the
+// query's own input are returned; paths inside function arguments and on the right of
+// pipes apply to other values and are skipped.
+func jqRootPaths(query *gojq.Query) [][]string {
+	paths := [][]string{}
+	var visitQuery func(q *gojq.Query)
+	var visitTerm func(t *gojq.Term)
+
+	visitQuery = func(q *gojq.Query) {
+		if q == nil {
+			return
+		}
+		if q.Term != nil {
+			visitTerm(q.Term)
+		}
+		visitQuery(q.Left)
+		if q.Op != gojq.OpPipe {
+			visitQuery(q.Right)
+		}
+	}
+
+	visitTerm = func(t *gojq.Term) {
+		switch t.Type {
+		case gojq.TermTypeIndex:
+			if path := jqIndexPath(t); len(path) > 0 {
+				paths = append(paths, path)
+			}
+		case gojq.TermTypeQuery:
+			visitQuery(t.Query)
+		case gojq.TermTypeObject:
+			for _, keyVal := range t.Object.KeyVals {
+				visitQuery(keyVal.KeyQuery)
+				visitQuery(keyVal.Val)
+			}
+		case gojq.TermTypeArray:
+			visitQuery(t.Array.Query)
+		case gojq.TermTypeUnary:
+			visitTerm(t.Unary.Term)
+		case gojq.TermTypeIf:
+			visitQuery(t.If.Cond)
+			visitQuery(t.If.Then)
+			for _, elif := range t.If.Elif {
+				visitQuery(elif.Cond)
+				visitQuery(elif.Then)
+			}
+			visitQuery(t.If.Else)
+		case gojq.TermTypeTry:
+			visitQuery(t.Try.Body)
+			visitQuery(t.Try.Catch)
+		}
+		if t.Str != nil {
+			for _, q := range t.Str.Queries {
+				visitQuery(q)
+			}
+		}
+		for _, suffix := range t.SuffixList {
+			if suffix.Bind != nil {
+				// `term as $x | body` runs body against the same input as term
+				visitQuery(suffix.Bind.Body)
+			}
+		}
+	}
+
+	visitQuery(query)
+	return paths
+}
+
+// jqIndexPath returns the leading object keys of an index term such as .a.b["c"][0],
+// stopping at the first index that isn't a constant key.
+func jqIndexPath(t *gojq.Term) []string {
+	path := []string{}
+	key, ok := jqIndexKey(t.Index)
+	if !ok {
+		return path
+	}
+	path = append(path, key)
+	for _, suffix := range t.SuffixList {
+		if suffix.Index == nil {
+			break
+		}
+		if key, ok = jqIndexKey(suffix.Index); !ok {
+			break
+		}
+		path = append(path, key)
+	}
+	return path
+}
+
+func jqIndexKey(index *gojq.Index) (string, bool) {
+	switch {
+	case index == nil || index.IsSlice || index.Start != nil:
+		return "", false
+	case index.Name != "":
+		return index.Name, true
+	case index.Str != nil && len(index.Str.Queries) == 0:
+		return index.Str.Str, true
+	}
+	return "", false
+}
@@ -203,6 +203,53 @@ func TestLintInputsMatchProvisioner(t *testing.T) {
 						},
 					},
 				},
+			}, {
+				name: "Valid wired input from earlier step",
+				bun: &bundle.Bundle{
+					Name:        "example",
+					Description: "description",
+					Type:        "infrastructure",
+					Steps: []bundle.Step{{
+						Path:        "testdata/lint/network",
+						Provisioner: "opentofu",
+					}, {
+						Path:        "testdata/lint/module",
+						Provisioner: "opentofu",
+						InputsFrom:  []bundle.StepInput{{Step: "testdata/lint/network", Output: "bar"}},
+					}},
+					Params: map[string]any{
+						"properties": map[string]any{
+							"foo": map[string]any{},
+						},
+					},
+					Connections: map[string]any{},
+					Artifacts:   map[string]any{},
+					UI:          map[string]any{},
+				},
+				want: bundle.LintResult{},
+			}, {
+				name: "Valid step selects a subset of params",
+				bun: &bundle.Bundle{
+					Name:        "example",
+					Description: "description",
+					Type:        "infrastructure",
+					Steps: []bundle.Step{{
+						Path:        "testdata/lint/module",
+						Provisioner: "opentofu",
+						Inputs:      []string{"foo", "/params/bar"},
+					}},
+					Params: map[string]any{
+						"properties": map[string]any{
+							"foo": map[string]any{},
+							"bar": map[string]any{},
+							"baz": map[string]any{},
+						},
+					},
+					Connections: map[string]any{},
+					Artifacts:   map[string]any{},
+					UI:          map[string]any{},
+				},
+				want: bundle.LintResult{},
 			}, {
 				name: "Invalid missing IaC input",
 				bun: &bundle.Bundle{
@@ -248,6 +295,151 @@ func TestLintInputsMatchProvisioner(t *testing.T) {
 	}
 }
 
+func TestLintStepInputs(t *testing.T) {
+	type test struct {
+		name  string
+		steps []bundle.Step
+		want  bundle.LintResult
+	}
+	tests := []test{
+		{
+			name: "Valid output from earlier step",
+			steps: []bundle.Step{
+				{Path: "testdata/lint/network", Provisioner: "opentofu"},
+				{Path: "testdata/lint/module", Provisioner: "opentofu", InputsFrom: []bundle.StepInput{{Step: "testdata/lint/network", Output: "bar"}}},
+			},
+			want: bundle.LintResult{},
+		},
+		{
+			name: "Invalid unknown step",
+			steps: []bundle.Step{
+				{Path: "testdata/lint/module", Provisioner: "opentofu", InputsFrom: []bundle.StepInput{{Step: "cluster", Output: "bar"}}},
+			},
+			want: bundle.LintResult{
+				Issues: []bundle.LintIssue{{
+					Rule:     "step-inputs",
+					Severity: bundle.LintError,
+					Message:  `step testdata/lint/module wires output "bar" from unknown step "cluster"`,
+				}},
+			},
+		},
+		{
+			name: "Invalid later step",
+			steps: []bundle.Step{
+				{Path: "testdata/lint/module", Provisioner: "opentofu", InputsFrom: []bundle.StepInput{{Step: "testdata/lint/network", Output: "bar"}}},
+				{Path: "testdata/lint/network", Provisioner: "opentofu"},
+			},
+			want: bundle.LintResult{
+				Issues: []bundle.LintIssue{{
+					Rule:     "step-inputs",
+					Severity: bundle.LintError,
+					Message:  `step testdata/lint/module wires output "bar" from step "testdata/lint/network", which does not run before it`,
+				}},
+			},
+		},
+		{
+			name: "Invalid undeclared output",
+			steps: []bundle.Step{
+				{Path: "testdata/lint/network", Provisioner: "opentofu"},
+				{Path: "testdata/lint/module", Provisioner: "opentofu", InputsFrom: []bundle.StepInput{{Step: "testdata/lint/network", Output: "endpoint"}}},
+			},
+			want: bundle.LintResult{
+				Issues: []bundle.LintIssue{{
+					Rule:     "step-inputs",
+					Severity: bundle.LintError,
+					Message:  `step testdata/lint/module wires output "endpoint" from step testdata/lint/network, but that step's IaC doesn't declare it`,
+				}},
+			},
+		},
+		{
+			name: "Invalid collision with param",
+			steps: []bundle.Step{
+				{Path: "testdata/lint/network", Provisioner: "opentofu"},
+				{Path: "testdata/lint/module", Provisioner: "opentofu", InputsFrom: []bundle.StepInput{{Step: "testdata/lint/network", Output: "foo"}}},
+			},
+			want: bundle.LintResult{
+				Issues: []bundle.LintIssue{
+					{
+						Rule:     "step-inputs",
+						Severity: bundle.LintError,
+						Message:  `step testdata/lint/module wires output "foo", which collides with a param, connection or metadata input of the same name`,
+					},
+					{
+						Rule:     "step-inputs",
+						Severity: bundle.LintError,
+						Message:  `step testdata/lint/module wires output "foo" from step testdata/lint/network, but that step's IaC doesn't declare it`,
+					},
+				},
+			},
+		},
+		{
+			name: "Invalid unresolved selection",
+			steps: []bundle.Step{
+				{Path: "testdata/lint/module", Provisioner: "opentofu", Inputs: []string{"foo", "/params/foo", "/params", "baz", "/outputs"}},
+			},
+			want: bundle.LintResult{
+				Issues: []bundle.LintIssue{
+					{
+						Rule:     "step-inputs",
+						Severity: bundle.LintError,
+						Message:  `step testdata/lint/module selects input "baz", which doesn't match any param or connection`,
+					},
+					{
+						Rule:     "step-inputs",
+						Severity: bundle.LintError,
+						Message:  `step testdata/lint/module selects input "/outputs", which doesn't match any param or connection`,
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			bun := &bundle.Bundle{
+				Name:  "example",
+				Steps: tc.steps,
+				Params: map[string]any{
+					"properties": map[string]any{
+						"foo": map[string]any{},
+					},
+				},
+			}
+			got := bun.LintStepInputs()
+
+			assert.ElementsMatch(t, tc.want.Issues, got.Issues)
+		})
+	}
+}
+
+func TestLintStepConfig(t *testing.T) {
+	b, err := bundle.Unmarshal("testdata/steps")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := b.LintStepConfig()
+
+	if errs := got.Errors(); len(errs) != 0 {
+		t.Errorf("got errors %v, want none", errs)
+	}
+
+	// unknown fields and config keys are only warnings, since the platform may read keys the schemas don't list
+	want := []string{
+		`step network has unknown field "skip_on_delet", did you mean "skip_on_delete"?`,
+		`step chart has unknown helm config key "relase_name", did you mean "release_name"?`,
+	}
+	warnings := got.Warnings()
+	if len(warnings) != len(want) {
+		t.Fatalf("got %d warnings, want %d: %v", len(warnings), len(want), warnings)
+	}
+	for i, warning := range warnings {
+		if warning.Rule != "step-config" || warning.Message != want[i] {
+			t.Errorf("got warning %v, want %q", warning, want[i])
+		}
+	}
+}
+
 func TestLintMatchRequired(t *testing.T) {
 	type test struct {
 		name string
@@ -372,3 +564,146 @@ func TestLintMatchRequired(t *testing.T) {
 		})
 	}
 }
+
+func TestLintAppSpec(t *testing.T) {
+	postgres := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"data": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"authentication": map[string]any{
+						"type": "object",
+						"properties": map[string]any{
+							"hostname": map[string]any{"type": "string"},
+							"port":     map[string]any{"type": "integer"},
+						},
+					},
+					"security": map[string]any{
+						"type": "object",
+						"properties": map[string]any{
+							"iam": map[string]any{
+								"type":                 "object",
+								"additionalProperties": map[string]any{"type": "object"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	newBundle := func(app *bundle.AppSpec) *bundle.Bundle {
+		return &bundle.Bundle{
+			Params: map[string]any{
+				"properties": map[string]any{
+					"log_level": map[string]any{"type": "string"},
+					"tags":      map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "string"}},
+				},
+			},
+			Connections: map[string]any{"properties": map[string]any{"postgres": postgres}},
+			AppSpec:     app,
+		}
+	}
+
+	type test struct {
+		name string
+		app  *bundle.AppSpec
+		want []bundle.LintIssue
+	}
+	tests := []test{
+		{
+			name: "no app block",
+		},
+		{
+			name: "valid",
+			app: &bundle.AppSpec{
+				Envs: map[string]string{
+					"DATABASE_URL": `@text "postgres://" + .connections.postgres.data.authentication.hostname + ":" + (.connections.postgres.data.authentication.port|tostring)`,
+					"LOG_LEVEL":    `.params.log_level // "info"`,
+					"TEAM":         `.params.tags.team`,
+					"PREFIX":       `"\(.md_metadata.name_prefix)-api"`,
+					"HOSTS":        `.params | keys | map(.unrelated)`,
+				},
+				Policies: []string{".connections.postgres.data.security.iam.read"},
+				Secrets: map[string]bundle.Secret{
+					"STRIPE_KEY":  {Required: true},
+					"GCP_SA_JSON": {JSON: true, Description: "A service account key file"},
+				},
+			},
+		},
+		{
+			name: "invalid envs",
+			app: &bundle.AppSpec{
+				Envs: map[string]string{
+					"1BAD":      `.params.log_level`,
+					"BROKEN":    `.params.log_level +`,
+					"MISSING":   `.params.loglevel`,
+					"DEEP":      `if .params.log_level then .connections.postgres.data.authentication.username else "" end`,
+					"UNKNOWN":   `.secrets.token`,
+					"NOT_CONN":  `.connections.redis.data`,
+					"META_TYPO": `.md_metadata.name_prefx`,
+				},
+			},
+			want: []bundle.LintIssue{
+				{Rule: "app-envs", Severity: bundle.LintError, Message: "env 1BAD is not a valid environment variable name"},
+				{Rule: "app-envs", Severity: bundle.LintError, Message: "env BROKEN has an invalid jq query"},
+				{Rule: "app-envs", Severity: bundle.LintError, Message: "env DEEP references .connections.postgres.data.authentication.username, which isn't declared in connections"},
+				{Rule: "app-envs", Severity: bundle.LintError, Message: "env META_TYPO references .md_metadata.name_prefx, which isn't declared in md_metadata"},
+				{Rule: "app-envs", Severity: bundle.LintError, Message: "env MISSING references .params.loglevel, which isn't declared in params"},
+				{Rule: "app-envs", Severity: bundle.LintError, Message: "env NOT_CONN references .connections.redis, which isn't declared in connections"},
+				{Rule: "app-envs", Severity: bundle.LintError, Message: "env UNKNOWN references .secrets.token, but only .params, .connections and .md_metadata are available"},
+			},
+		},
+		{
+			name: "invalid policies",
+			app: &bundle.AppSpec{
+				Policies: []string{
+					".connections.postgres.data.securty.iam.read",
+					".params.log_level",
+					".connections.postgres | .data",
+					".connections.redis.data.security.iam.read",
+				},
+			},
+			want: []bundle.LintIssue{
+				{Rule: "app-policies", Severity: bundle.LintError, Message: "policy .connections.postgres.data.securty.iam.read references .connections.postgres.data.securty, which isn't declared in connections"},
+				{Rule: "app-policies", Severity: bundle.LintError, Message: "policy .params.log_level must reference a connection"},
+				{Rule: "app-policies", Severity: bundle.LintError, Message: "policy .connections.postgres | .data must be a plain path"},
+				{Rule: "app-policies", Severity: bundle.LintError, Message: "policy .connections.redis.data.security.iam.read references .connections.redis, which isn't declared in connections"},
+			},
+		},
+		{
+			name: "invalid secrets",
+			app: &bundle.AppSpec{
+				Envs: map[string]string{"API_KEY": `.params.log_level`},
+				Secrets: map[string]bundle.Secret{
+					"API_KEY":     {},
+					"api-token":   {},
+					"stripe_key":  {},
+					"CREDENTIALS": {JSON: true},
+				},
+			},
+			want: []bundle.LintIssue{
+				{Rule: "app-secrets", Severity: bundle.LintError, Message: "secret API_KEY has the same name as an env"},
+				{Rule: "app-secrets", Severity: bundle.LintWarning, Message: "secret CREDENTIALS is JSON but has no description"},
+				{Rule: "app-secrets", Severity: bundle.LintError, Message: "secret api-token is not a valid environment variable name"},
+				{Rule: "app-secrets", Severity: bundle.LintWarning, Message: "secret stripe_key should be upper case, like STRIPE_KEY"},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := newBundle(tc.app).LintAppSpec()
+
+			assert.Len(t, got.Issues, len(tc.want), "%v", got.Issues)
+			for i := range tc.want {
+				if i >= len(got.Issues) {
+					break
+				}
+				assert.Equal(t, tc.want[i].Rule, got.Issues[i].Rule)
+				assert.Equal(t, tc.want[i].Severity, got.Issues[i].Severity)
+				assert.Contains(t, got.Issues[i].Message, tc.want[i].Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package bundle
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/massdriver-cloud/mass/internal/files"
+)
+
+const (
+	// PlanActionProvision plans the steps run when a package is deployed.
+	PlanActionProvision = "provision"
+	// PlanActionDecommission plans the steps run when a package is decommissioned.
+	PlanActionDecommission = "decommission"
+)
+
+// PlannedStep is one entry of an execution plan produced by PlanSteps.
+type PlannedStep struct {
+	Step
+	Skipped bool
+}
+
+// PlanSteps returns the steps in the order they run for action. Provisioning runs steps
+// in declaration order; decommissioning runs them in reverse and skips any step marked
+// skip_on_delete.
+func (b *Bundle) PlanSteps(action string) ([]PlannedStep, error) {
+	plan := make([]PlannedStep, 0, len(b.Steps))
+	switch action {
+	case PlanActionProvision:
+		for _, step := range b.Steps {
+			plan = append(plan, PlannedStep{Step: step})
+		}
+	case PlanActionDecommission:
+		for _, step := range slices.Backward(b.Steps) {
+			plan = append(plan, PlannedStep{Step: step, Skipped: step.SkipOnDelete})
+		}
+	default:
+		return nil, fmt.Errorf("unsupported action %q, must be one of: %s, %s", action, PlanActionProvision, PlanActionDecommission)
+	}
+	return plan, nil
+}
+
+// readUnknownStepFields returns, for each step in the massdriver.yaml at path, the keys that
+// don't map to a Step field. These are otherwise dropped silently during unmarshalling.
+func readUnknownStepFields(path string) ([][]string, error) {
+	raw := struct {
+		Steps []map[string]any `json:"steps"`
+	}{}
+	if err := files.Read(path, &raw); err != nil {
+		return nil, err
+	}
+
+	known := stepFieldNames()
+	unknown := make([][]string, len(raw.Steps))
+	for i, step := range raw.Steps {
+		for key := range step {
+			if !slices.Contains(known, key) {
+				unknown[i] = append(unknown[i], key)
+			}
+		}
+		slices.Sort(unknown[i])
+	}
+	return unknown, nil
+}
+
+func stepFieldNames() []string {
+	stepType := reflect.TypeFor[Step]()
+	names := make([]string, 0, stepType.NumField())
+	for i := range stepType.NumField() {
+		name, _, _ := strings.Cut(stepType.Field(i).Tag.Get("json"), ",")
+		names = append(names, name)
+	}
+	return names
+}
+
+// closestStepField suggests the Step field a misspelled key was likely meant to be.
+func closestStepField(key string) string {
+	return closestName(key, stepFieldNames())
+}
+
+// closestName suggests the name a misspelled key was likely meant to be, or returns an
+// empty string when none is close.
+func closestName(key string, names []string) string {
+	const maxDistance = 2
+	best, bestDistance := "", maxDistance+1
+	for _, name := range names {
+		if d := editDistance(key, name); d < bestDistance {
+			best, bestDistance = name, d
+		}
+	}
+	return best
+}
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,67 @@
+package bundle_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/massdriver-cloud/mass/internal/bundle"
+)
+
+func TestPlanSteps(t *testing.T) {
+	steps := []bundle.Step{
+		{Path: "network", Provisioner: "opentofu", SkipOnDelete: true},
+		{Path: "cluster", Provisioner: "opentofu"},
+		{Path: "chart", Provisioner: "helm", Config: map[string]any{"namespace": "default"}},
+	}
+
+	type test struct {
+		name    string
+		action  string
+		want    []bundle.PlannedStep
+		wantErr string
+	}
+	tests := []test{
+		{
+			name:   "provision runs steps in order",
+			action: bundle.PlanActionProvision,
+			want: []bundle.PlannedStep{
+				{Step: steps[0]},
+				{Step: steps[1]},
+				{Step: steps[2]},
+			},
+		},
+		{
+			name:   "decommission runs steps in reverse and skips skip_on_delete",
+			action: bundle.PlanActionDecommission,
+			want: []bundle.PlannedStep{
+				{Step: steps[2]},
+				{Step: steps[1]},
+				{Step: steps[0], Skipped: true},
+			},
+		},
+		{
+			name:    "unknown action",
+			action:  "destroy",
+			wantErr: `unsupported action "destroy", must be one of: provision, decommission`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			b := &bundle.Bundle{Steps: steps}
+			got, err := b.PlanSteps(tc.action)
+			if tc.wantErr != "" {
+				if err == nil || err.Error() != tc.wantErr {
+					t.Fatalf("got error %v, want %s", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package bundle
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// StepInput wires an output of an earlier step into a step as an input variable
+// of the same name.
+type StepInput struct {
+	Step   string `json:"step" yaml:"step" mapstructure:"step"`
+	Output string `json:"output" yaml:"output" mapstructure:"output"`
+}
+
+// wiredInputNames returns the names of the variables a step receives from earlier steps.
+func (s Step) wiredInputNames() []string {
+	names := make([]string, 0, len(s.InputsFrom))
+	for _, input := range s.InputsFrom {
+		names = append(names, input.Output)
+	}
+	return names
+}
+
+// selectedInputNames resolves a step's inputs selection to param and connection names.
+// Each entry is either a bare input name or a JSON pointer: /params and /connections
+// select every param or connection, /params/<name> and /connections/<name> select one.
+// Entries that don't resolve are returned separately. A nil selection means the step
+// receives every input.
+func (b *Bundle) selectedInputNames(step Step) ([]string, []string) {
+	if len(step.Inputs) == 0 {
+		return nil, nil
+	}
+
+	sections := map[string][]string{
+		"params":      schemaPropertyNames(b.Params),
+		"connections": schemaPropertyNames(b.Connections),
+	}
+
+	selected := []string{}
+	unresolved := []string{}
+	for _, entry := range step.Inputs {
+		var names []string
+		if pointer, isPointer := strings.CutPrefix(entry, "/"); isPointer {
+			names = resolveInputPointer(sections, pointer)
+		} else if slices.Contains(sections["params"], entry) || slices.Contains(sections["connections"], entry) {
+			names = []string{entry}
+		}
+
+		if len(names) == 0 {
+			unresolved = append(unresolved, entry)
+			continue
+		}
+		for _, name := range names {
+			if !slices.Contains(selected, name) {
+				selected = append(selected, name)
+			}
+		}
+	}
+
+	return selected, unresolved
+}
+
+func resolveInputPointer(sections map[string][]string, pointer string) []string {
+	tokens := strings.Split(pointer, "/")
+	for i, token := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+
+	names, ok := sections[tokens[0]]
+	if !ok {
+		return nil
+	}
+	switch len(tokens) {
+	case 1:
+		return names
+	case 2:
+		if slices.Contains(names, tokens[1]) {
+			return []string{tokens[1]}
+		}
+	}
+	return nil
+}
+
+func schemaPropertyNames(sch map[string]any) []string {
+	props, _ := sch["properties"].(map[string]any)
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// stepInputs narrows the combined params, connections and metadata to the step's inputs
+// selection. Metadata is always kept since Massdriver passes it to every step.
+func (b *Bundle) stepInputs(step Step, combined map[string]any) map[string]any {
+	selected, _ := b.selectedInputNames(step)
+	if selected == nil {
+		return combined
+	}
+	selected = append(selected, schemaPropertyNames(MetadataSchema)...)
+
+	combinedProps, _ := combined["properties"].(map[string]any)
+	combinedReq, _ := combined["required"].([]any)
+
+	props := map[string]any{}
+	for _, name := range selected {
+		if prop, ok := combinedProps[name]; ok {
+			props[name] = prop
+		}
+	}
+	required := []any{}
+	for _, name := range combinedReq {
+		if nameStr, ok := name.(string); ok && slices.Contains(selected, nameStr) {
+			required = append(required, name)
+		}
+	}
+
+	inputs := maps.Clone(combined)
+	inputs["properties"] = props
+	inputs["required"] = required
+	return inputs
+}
+
+// stepVariables returns the variables exported to a step: its selection of the combined
+// params, connections and metadata, plus a required variable for every wired output.
+func (b *Bundle) stepVariables(step Step, combined map[string]any) map[string]any {
+	inputs := b.stepInputs(step, combined)
+	if len(step.InputsFrom) == 0 {
+		return inputs
+	}
+
+	inputProps, _ := inputs["properties"].(map[string]any)
+	inputReq, _ := inputs["required"].([]any)
+
+	props := maps.Clone(inputProps)
+	if props == nil {
+		props = map[string]any{}
+	}
+	required := slices.Clone(inputReq)
+	for _, input := range step.InputsFrom {
+		props[input.Output] = map[string]any{
+			"description": fmt.Sprintf("Output %q of step %q.", input.Output, input.Step),
+		}
+		if !slices.Contains(required, any(input.Output)) {
+			required = append(required, input.Output)
+		}
+	}
+
+	variables := maps.Clone(inputs)
+	variables["properties"] = props
+	variables["required"] = required
+	return variables
+}
@@ -0,0 +1,11 @@
+variable "foo" {
+    type = string
+}
+
+variable "md_metadata" {
+    type = object({})
+}
+
+output "bar" {
+    value = length(var.foo)
+}
@@ -37,8 +37,24 @@ func MarkdownEscape(s string) string {
 	return markdownEscapeReplacer.Replace(s)
 }
 
+// markdownTableCellReplacer keeps a string on a single table row: pipes would
+// end the cell and newlines would end the row.
+var markdownTableCellReplacer = strings.NewReplacer(
+	"|", `\|`,
+	"\r\n", " ",
+	"\n", " ",
+)
+
+// MarkdownTableCell makes a string safe to place in a markdown table cell
+// without escaping anything else, so text meant to be read as raw markdown
+// (e.g. a generated README) stays readable.
+func MarkdownTableCell(s string) string {
+	return strings.TrimSpace(markdownTableCellReplacer.Replace(s))
+}
+
 // MarkdownTemplateFuncs are the helper funcs available in every glamour-fed
 // markdown template. Pass to `template.New(...).Funcs(...)`.
 var MarkdownTemplateFuncs = template.FuncMap{
 	"mdEscape": MarkdownEscape,
+	"mdCell":   MarkdownTableCell,
 }
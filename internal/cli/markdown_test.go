@@ -37,6 +37,27 @@ func TestMarkdownEscape(t *testing.T) {
 	}
 }
 
+func TestMarkdownTableCell(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "empty", in: "", want: ""},
+		{name: "markdown left alone", in: "The `cost_center` tag", want: "The `cost_center` tag"},
+		{name: "pipe", in: "a|b", want: `a\|b`},
+		{name: "newlines", in: "first line\nsecond line\r\nthird\n", want: "first line second line third"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := cli.MarkdownTableCell(tc.in)
+			if got != tc.want {
+				t.Errorf("MarkdownTableCell(%q) = %q, want %q", tc.in, got, tc.want)
+			}
+		})
+	}
+}
+
 func TestMarkdownTemplateFuncs(t *testing.T) {
 	tmpl, err := template.New("t").Funcs(cli.MarkdownTemplateFuncs).Parse(`{{ . | mdEscape }}`)
 	if err != nil {
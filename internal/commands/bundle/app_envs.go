@@ -0,0 +1,125 @@
+package bundle
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+
+	"github.com/massdriver-cloud/mass/internal/bundle"
+	"github.com/massdriver-cloud/mass/internal/cli"
+	"github.com/massdriver-cloud/mass/internal/files"
+	"github.com/massdriver-cloud/mass/internal/params"
+	"github.com/massdriver-cloud/mass/internal/prettylogs"
+)
+
+// AppEnvsOptions controls RunAppEnvs.
+type AppEnvsOptions struct {
+	// ParamsPath is a params file (.json, .yaml or .tfvars). Params are empty without one.
+	ParamsPath string
+	// ConnectionsPath is a file of connection artifacts keyed by connection name.
+	// Connections are empty without one.
+	ConnectionsPath string
+	// MetadataPath is a file with the md_metadata to use. Without one, placeholder
+	// metadata is generated from the metadata schema.
+	MetadataPath string
+	// Output is text or json. Defaults to text.
+	Output string
+	// Out receives the results. Defaults to stdout.
+	Out io.Writer
+}
+
+// RunAppEnvs evaluates the jq queries of the bundle's app.envs against the same input
+// document the platform builds at deploy time, made of params, connections and
+// md_metadata, and prints each env var's value or error. It returns an error if any of
+// them fail, since a deployment would fail the same way.
+func RunAppEnvs(b *bundle.Bundle, opts AppEnvsOptions) error {
+	if opts.Output == "" {
+		opts.Output = "text"
+	}
+	if opts.Out == nil {
+		opts.Out = os.Stdout
+	}
+	if b.AppSpec == nil || len(b.AppSpec.Envs) == 0 {
+		return errors.New("massdriver.yaml has no app.envs to evaluate")
+	}
+
+	input, err := appEnvsInput(opts)
+	if err != nil {
+		return err
+	}
+	results := bundle.ParseEnvironmentVariables(input, b.AppSpec.Envs)
+
+	switch opts.Output {
+	case "json":
+		encoded, marshalErr := json.MarshalIndent(results, "", "  ")
+		if marshalErr != nil {
+			return fmt.Errorf("failed to marshal results to JSON: %w", marshalErr)
+		}
+		fmt.Fprintln(opts.Out, string(encoded))
+	case "text":
+		tbl := cli.NewTable("Name", "Value").WithWriter(opts.Out)
+		for _, name := range slices.Sorted(maps.Keys(results)) {
+			result := results[name]
+			if result.Error != "" {
+				tbl.AddRow(name, prettylogs.Red("error: "+result.Error))
+				continue
+			}
+			tbl.AddRow(name, result.Value)
+		}
+		tbl.Print()
+	default:
+		return fmt.Errorf("unsupported output format: %s", opts.Output)
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.Error != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d environment variable(s) failed to evaluate", failed, len(results))
+	}
+	return nil
+}
+
+// appEnvsInput builds the document app.envs queries run against.
+func appEnvsInput(opts AppEnvsOptions) (map[string]any, error) {
+	input := map[string]any{
+		"params":      map[string]any{},
+		"connections": map[string]any{},
+	}
+	for key, path := range map[string]string{"params": opts.ParamsPath, "connections": opts.ConnectionsPath} {
+		if path == "" {
+			continue
+		}
+		value := map[string]any{}
+		if err := files.Read(path, &value); err != nil {
+			return nil, fmt.Errorf("failed to read %s file %s: %w", key, path, err)
+		}
+		input[key] = value
+	}
+
+	if opts.MetadataPath != "" {
+		metadata := map[string]any{}
+		if err := files.Read(opts.MetadataPath, &metadata); err != nil {
+			return nil, fmt.Errorf("failed to read metadata file %s: %w", opts.MetadataPath, err)
+		}
+		input["md_metadata"] = metadata
+	} else {
+		input["md_metadata"] = placeholderMetadata()
+	}
+	return input, nil
+}
+
+// placeholderMetadata generates md_metadata from the metadata schema, for running
+// app.envs queries without a real deployment.
+func placeholderMetadata() any {
+	metadataProps, _ := bundle.MetadataSchema["properties"].(map[string]any)
+	metadataSchema, _ := metadataProps["md_metadata"].(map[string]any)
+	return params.Example(metadataSchema, params.ExampleOptions{})
+}
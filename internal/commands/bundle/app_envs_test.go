@@ -0,0 +1,89 @@
+package bundle_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	bundlepkg "github.com/massdriver-cloud/mass/internal/bundle"
+	"github.com/massdriver-cloud/mass/internal/commands/bundle"
+)
+
+func TestRunAppEnvs(t *testing.T) {
+	type test struct {
+		name    string
+		opts    bundle.AppEnvsOptions
+		want    map[string]bundlepkg.ParsedEnvironmentVariable
+		wantErr string
+	}
+	tests := []test{
+		{
+			name: "all inputs",
+			opts: bundle.AppEnvsOptions{
+				ParamsPath:      "testdata/app-envs/params.json",
+				ConnectionsPath: "testdata/app-envs/connections.yaml",
+				MetadataPath:    "testdata/app-envs/metadata.json",
+			},
+			want: map[string]bundlepkg.ParsedEnvironmentVariable{
+				"ALLOWED_HOSTS": {Value: `["api.example.com"]`},
+				"DATABASE_PORT": {Value: "5432.000000"},
+				"LOG_LEVEL":     {Value: "info"},
+				"NAME_PREFIX":   {Value: "ecomm-prod-api"},
+			},
+		},
+		{
+			name: "missing connection and placeholder metadata",
+			opts: bundle.AppEnvsOptions{
+				ParamsPath: "testdata/app-envs/params.json",
+			},
+			want: map[string]bundlepkg.ParsedEnvironmentVariable{
+				"ALLOWED_HOSTS": {Value: `["api.example.com"]`},
+				"DATABASE_PORT": {Error: "failed to produce a result"},
+				"LOG_LEVEL":     {Value: "info"},
+				"NAME_PREFIX":   {Value: "example"},
+			},
+			wantErr: "1 of 4 environment variable(s) failed to evaluate",
+		},
+	}
+
+	b, err := bundlepkg.Unmarshal("testdata/app-envs")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			tc.opts.Output = "json"
+			tc.opts.Out = &out
+
+			err := bundle.RunAppEnvs(b, tc.opts)
+			if tc.wantErr == "" && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if tc.wantErr != "" && (err == nil || err.Error() != tc.wantErr) {
+				t.Fatalf("got error %v, want %s", err, tc.wantErr)
+			}
+
+			got := map[string]bundlepkg.ParsedEnvironmentVariable{}
+			if unmarshalErr := json.Unmarshal(out.Bytes(), &got); unmarshalErr != nil {
+				t.Fatalf("unexpected error: %s", unmarshalErr)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRunAppEnvsWithoutEnvs(t *testing.T) {
+	b, err := bundlepkg.Unmarshal("testdata/docs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.AppSpec = nil
+	if err = bundle.RunAppEnvs(b, bundle.AppEnvsOptions{Out: &bytes.Buffer{}}); err == nil {
+		t.Error("expected an error for a bundle without app.envs")
+	}
+}
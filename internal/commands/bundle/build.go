@@ -9,6 +9,6 @@ import (
 )
 
 // RunBuild builds the bundle at buildPath using the provided bundle and client.
-func RunBuild(buildPath string, b *bundle.Bundle, mdClient *massdriver.Client) error {
-	return b.Build(buildPath, resourcetype.NewMassdriverResolver(mdClient))
+func RunBuild(buildPath string, b *bundle.Bundle, mdClient *massdriver.Client, opts bundle.BuildOptions) error {
+	return b.BuildWithOptions(buildPath, resourcetype.NewMassdriverResolver(mdClient), opts)
 }
@@ -0,0 +1,333 @@
+package bundle
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/massdriver-cloud/mass/internal/bundle"
+	"github.com/massdriver-cloud/mass/internal/cli"
+	"github.com/massdriver-cloud/massdriver-sdk-go/massdriver"
+)
+
+//go:embed templates/docs.md.tmpl
+var docsTemplate string
+
+// The generated docs replace everything between these markers when injected into an
+// existing markdown file.
+const (
+	DocsBeginMarker = "<!-- BEGIN_MASS_BUNDLE_DOCS -->"
+	DocsEndMarker   = "<!-- END_MASS_BUNDLE_DOCS -->"
+)
+
+// DocsOptions controls RunDocs. With neither path set the docs are printed.
+type DocsOptions struct {
+	// OutputPath is a file to write the full document to.
+	OutputPath string
+	// InjectPath is an existing markdown file whose marker comments the docs are
+	// injected between, leaving the rest of the file alone.
+	InjectPath string
+	// Out receives the docs or status messages. Defaults to stdout.
+	Out io.Writer
+}
+
+type docsData struct {
+	Standalone  bool
+	Name        string
+	Description string
+	Version     string
+	SourceURL   string
+	Params      []docsParam
+	Connections []docsDependency
+	Artifacts   []docsDependency
+	Steps       []bundle.Step
+	App         *docsApp
+}
+
+type docsParam struct {
+	Name        string
+	Type        string
+	Required    bool
+	Default     string
+	Constraints string
+	Description string
+}
+
+type docsDependency struct {
+	Name         string
+	Required     bool
+	ResourceType string
+	Description  string
+}
+
+type docsApp struct {
+	Envs     []docsEnv
+	Secrets  []docsSecret
+	Policies []string
+}
+
+type docsEnv struct {
+	Name  string
+	Query string
+}
+
+type docsSecret struct {
+	Name string
+	bundle.Secret
+}
+
+// RunDocs renders markdown documentation of the bundle's params, connections,
+// artifacts, steps and app configuration from its massdriver.yaml.
+func RunDocs(buildPath string, mdClient *massdriver.Client, opts DocsOptions) error {
+	if opts.Out == nil {
+		opts.Out = os.Stdout
+	}
+
+	b, err := bundle.Unmarshal(buildPath)
+	if err != nil {
+		return err
+	}
+	docs, err := RenderDocs(b, buildPath, mdClient, opts.InjectPath == "")
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case opts.InjectPath != "":
+		existing, readErr := os.ReadFile(opts.InjectPath)
+		if readErr != nil {
+			return readErr
+		}
+		injected, injectErr := InjectDocs(string(existing), docs)
+		if injectErr != nil {
+			return fmt.Errorf("%s: %w", opts.InjectPath, injectErr)
+		}
+		if writeErr := os.WriteFile(opts.InjectPath, []byte(injected), 0644); writeErr != nil {
+			return writeErr
+		}
+		fmt.Fprintf(opts.Out, "Updated the bundle docs in %s\n", opts.InjectPath)
+	case opts.OutputPath != "":
+		if writeErr := os.WriteFile(opts.OutputPath, []byte(docs), 0644); writeErr != nil {
+			return writeErr
+		}
+		fmt.Fprintf(opts.Out, "Wrote the bundle docs to %s\n", opts.OutputPath)
+	default:
+		fmt.Fprint(opts.Out, docs)
+	}
+	return nil
+}
+
+// RenderDocs renders the bundle's documentation as markdown. Standalone documents start
+// with the bundle's name and description; otherwise only the sections are rendered, for
+// injecting into a hand-written README. Params $refs are resolved as bundle build resolves them.
+func RenderDocs(b *bundle.Bundle, buildPath string, mdClient *massdriver.Client, standalone bool) (string, error) {
+	paramsSchema, err := b.DereferenceParams(buildPath, schemaResolver(mdClient))
+	if err != nil {
+		return "", err
+	}
+
+	data := docsData{
+		Standalone:  standalone,
+		Name:        b.Name,
+		Description: b.Description,
+		Version:     b.Version,
+		SourceURL:   b.SourceURL,
+		Params:      docsParams("", paramsSchema),
+		Connections: docsDependencies(b.Connections),
+		Artifacts:   docsDependencies(b.Artifacts),
+		Steps:       b.Steps,
+		App:         newDocsApp(b.AppSpec),
+	}
+
+	tmpl, err := template.New("docs").Funcs(cli.MarkdownTemplateFuncs).Parse(docsTemplate)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err = tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()) + "\n", nil
+}
+
+// InjectDocs replaces the content between DocsBeginMarker and DocsEndMarker in existing.
+func InjectDocs(existing string, docs string) (string, error) {
+	begin := strings.Index(existing, DocsBeginMarker)
+	end := strings.Index(existing, DocsEndMarker)
+	if begin == -1 || end == -1 || end < begin {
+		return "", fmt.Errorf("no %s ... %s markers to inject the docs between", DocsBeginMarker, DocsEndMarker)
+	}
+	return existing[:begin+len(DocsBeginMarker)] + "\n" + docs + existing[end:], nil
+}
+
+// docsParams flattens the properties of an object schema into rows, naming nested
+// properties by their path, e.g. database.instance_type or subnets[].cidr.
+func docsParams(prefix string, sch map[string]any) []docsParam {
+	props, _ := sch["properties"].(map[string]any)
+	required, _ := sch["required"].([]any)
+
+	rows := []docsParam{}
+	for _, name := range slices.Sorted(maps.Keys(props)) {
+		prop, _ := props[name].(map[string]any)
+		path := prefix + name
+		row := docsParam{
+			Name:        path,
+			Type:        docsType(prop),
+			Required:    slices.Contains(required, any(name)),
+			Constraints: docsConstraints(prop),
+			Description: docsDescription(prop),
+		}
+		if value, ok := prop["default"]; ok {
+			row.Default = docsValue(value)
+		}
+		rows = append(rows, row)
+
+		if _, isObject := prop["properties"]; isObject {
+			rows = append(rows, docsParams(path+".", prop)...)
+		}
+		if items, isArray := prop["items"].(map[string]any); isArray {
+			if _, isObject := items["properties"]; isObject {
+				rows = append(rows, docsParams(path+"[].", items)...)
+			}
+		}
+	}
+	return rows
+}
+
+func docsType(sch map[string]any) string {
+	switch schemaType := sch["type"].(type) {
+	case string:
+		if items, ok := sch["items"].(map[string]any); ok && schemaType == "array" {
+			if itemType := docsType(items); itemType != "" {
+				return "array of " + itemType
+			}
+		}
+		return schemaType
+	case []any:
+		types := make([]string, 0, len(schemaType))
+		for _, t := range schemaType {
+			types = append(types, fmt.Sprint(t))
+		}
+		return strings.Join(types, " or ")
+	}
+	for _, keyword := range []string{"oneOf", "anyOf"} {
+		if options, ok := sch[keyword].([]any); ok {
+			types := []string{}
+			for _, option := range options {
+				optionSchema, _ := option.(map[string]any)
+				if optionType := docsType(optionSchema); optionType != "" && !slices.Contains(types, optionType) {
+					types = append(types, optionType)
+				}
+			}
+			return strings.Join(types, " or ")
+		}
+	}
+	if _, ok := sch["properties"]; ok {
+		return "object"
+	}
+	return ""
+}
+
+// docsConstraintKeywords are the validation keywords listed under Constraints, with
+// their labels.
+var docsConstraintKeywords = []struct {
+	keyword string
+	label   string
+}{
+	{"minimum", "minimum"},
+	{"exclusiveMinimum", "exclusive minimum"},
+	{"maximum", "maximum"},
+	{"exclusiveMaximum", "exclusive maximum"},
+	{"multipleOf", "multiple of"},
+	{"minLength", "min length"},
+	{"maxLength", "max length"},
+	{"minItems", "min items"},
+	{"maxItems", "max items"},
+}
+
+func docsConstraints(sch map[string]any) string {
+	constraints := []string{}
+	if enum, ok := sch["enum"].([]any); ok {
+		values := make([]string, 0, len(enum))
+		for _, value := range enum {
+			values = append(values, "`"+docsValue(value)+"`")
+		}
+		constraints = append(constraints, "one of "+strings.Join(values, ", "))
+	}
+	if value, ok := sch["const"]; ok {
+		constraints = append(constraints, "always `"+docsValue(value)+"`")
+	}
+	for _, c := range docsConstraintKeywords {
+		if value, ok := sch[c.keyword]; ok {
+			constraints = append(constraints, fmt.Sprintf("%s %s", c.label, docsValue(value)))
+		}
+	}
+	if pattern, ok := sch["pattern"].(string); ok {
+		constraints = append(constraints, "pattern `"+pattern+"`")
+	}
+	if format, ok := sch["format"].(string); ok {
+		constraints = append(constraints, "format "+format)
+	}
+	if unique, _ := sch["uniqueItems"].(bool); unique {
+		constraints = append(constraints, "unique items")
+	}
+	return strings.Join(constraints, "; ")
+}
+
+func docsDescription(sch map[string]any) string {
+	if description, ok := sch["description"].(string); ok && description != "" {
+		return description
+	}
+	title, _ := sch["title"].(string)
+	return title
+}
+
+// docsValue renders a schema value as compact JSON, e.g. "us-west-2" or [1,2].
+func docsValue(value any) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(encoded)
+}
+
+// docsDependencies lists the connections or artifacts of a schema as written in
+// massdriver.yaml, so each keeps the resource type it $refs.
+func docsDependencies(sch map[string]any) []docsDependency {
+	props, _ := sch["properties"].(map[string]any)
+	required, _ := sch["required"].([]any)
+
+	dependencies := []docsDependency{}
+	for _, name := range slices.Sorted(maps.Keys(props)) {
+		prop, _ := props[name].(map[string]any)
+		ref, _ := prop["$ref"].(string)
+		dependencies = append(dependencies, docsDependency{
+			Name:         name,
+			Required:     slices.Contains(required, any(name)),
+			ResourceType: ref,
+			Description:  docsDescription(prop),
+		})
+	}
+	return dependencies
+}
+
+func newDocsApp(spec *bundle.AppSpec) *docsApp {
+	if spec == nil {
+		return nil
+	}
+	app := &docsApp{Policies: spec.Policies}
+	for _, name := range slices.Sorted(maps.Keys(spec.Envs)) {
+		app.Envs = append(app.Envs, docsEnv{Name: name, Query: spec.Envs[name]})
+	}
+	for _, name := range slices.Sorted(maps.Keys(spec.Secrets)) {
+		app.Secrets = append(app.Secrets, docsSecret{Name: name, Secret: spec.Secrets[name]})
+	}
+	return app
+}
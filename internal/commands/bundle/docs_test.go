@@ -0,0 +1,83 @@
+package bundle_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/massdriver-cloud/mass/internal/commands/bundle"
+)
+
+func TestRunDocs(t *testing.T) {
+	// run from the bundle directory, where bundle build resolves its ./database.json $ref
+	t.Chdir("testdata/docs")
+	var out bytes.Buffer
+	if err := bundle.RunDocs(".", nil, bundle.DocsOptions{Out: &out}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	want, err := os.ReadFile("expected-docs.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := out.String(); got != string(want) {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRunDocsInject(t *testing.T) {
+	readme := filepath.Join(t.TempDir(), "README.md")
+	original := "# aws-rds\n\nHand-written intro.\n\n" + bundle.DocsBeginMarker + "\nstale docs\n" + bundle.DocsEndMarker + "\n\n## Support\n\nAsk in #platform.\n"
+	if err := os.WriteFile(readme, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Chdir("testdata/docs")
+	opts := bundle.DocsOptions{InjectPath: readme, Out: &bytes.Buffer{}}
+	if err := bundle.RunDocs(".", nil, opts); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	first, err := os.ReadFile(readme)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := string(first)
+	if !strings.HasPrefix(got, "# aws-rds\n\nHand-written intro.\n\n"+bundle.DocsBeginMarker+"\n## Params\n") {
+		t.Errorf("expected the docs right after the begin marker, got:\n%s", got)
+	}
+	if !strings.HasSuffix(got, "- `.connections.network.specs.aws.read`\n"+bundle.DocsEndMarker+"\n\n## Support\n\nAsk in #platform.\n") {
+		t.Errorf("expected the rest of the README to be kept, got:\n%s", got)
+	}
+	if strings.Contains(got, "stale docs") {
+		t.Errorf("expected the stale docs to be replaced, got:\n%s", got)
+	}
+
+	if err = bundle.RunDocs(".", nil, opts); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	second, err := os.ReadFile(readme)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(second) != got {
+		t.Errorf("expected injecting twice to change nothing, got:\n%s", second)
+	}
+}
+
+func TestInjectDocsWithoutMarkers(t *testing.T) {
+	tests := map[string]string{
+		"none":     "# README\n",
+		"no end":   bundle.DocsBeginMarker + "\n",
+		"reversed": bundle.DocsEndMarker + "\n" + bundle.DocsBeginMarker + "\n",
+	}
+	for name, existing := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := bundle.InjectDocs(existing, "## Params\n"); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
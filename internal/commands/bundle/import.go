@@ -1,24 +1,66 @@
 package bundle
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"maps"
 	"os"
 	"path/filepath"
-	"strings"
+	"slices"
 
-	"github.com/manifoldco/promptui"
 	"github.com/massdriver-cloud/mass/internal/bundle"
+	"github.com/massdriver-cloud/mass/internal/prettylogs"
 	"github.com/massdriver-cloud/mass/internal/provisioners"
+	"github.com/massdriver-cloud/massdriver-sdk-go/massdriver"
 	yaml3 "gopkg.in/yaml.v3"
 )
 
+const (
+	// ImportOutputFile writes the imported params to massdriver.yaml.
+	ImportOutputFile = "file"
+	// ImportOutputPatch prints the change to massdriver.yaml as a patch instead of writing it.
+	ImportOutputPatch = "patch"
+)
+
+// ImportOptions controls how RunImport applies the params it finds.
+type ImportOptions struct {
+	// SkipVerify imports every missing param without prompting.
+	SkipVerify bool
+	// DryRun prints a diff of the proposed massdriver.yaml change instead of writing it.
+	DryRun bool
+	// Output is ImportOutputFile (the default) or ImportOutputPatch.
+	Output string
+	// Out receives the diff or patch. Defaults to stdout.
+	Out io.Writer
+	// MDClient looks up resource types, so object variables that match one can be imported
+	// as connections. Connections aren't detected when it is nil, or when the lookup fails.
+	MDClient *massdriver.Client
+}
+
 // RunImport checks for missing IaC parameters and updates massdriver.yaml with any found.
+// Only the params and ui sections are rewritten; the rest of the file is left byte-for-byte.
 //
 //nolint:funlen,gocognit
-func RunImport(buildPath string, skipVerify bool) error {
-	fmt.Println("Checking IaC for missing parameters...")
+func RunImport(buildPath string, opts ImportOptions) error {
+	out := opts.Out
+	if out == nil {
+		out = os.Stdout
+	}
+	if opts.Output == "" {
+		opts.Output = ImportOutputFile
+	}
+	if opts.Output != ImportOutputFile && opts.Output != ImportOutputPatch {
+		return fmt.Errorf("unknown output %q, must be one of: %s, %s", opts.Output, ImportOutputFile, ImportOutputPatch)
+	}
+	// keep status messages out of a patch written to stdout
+	status := io.Writer(os.Stdout)
+	if opts.Output == ImportOutputPatch {
+		status = os.Stderr
+	}
+
+	fmt.Fprintln(status, "Checking IaC for missing parameters...")
 
 	mdYamlPath := filepath.Join(buildPath, "massdriver.yaml")
 	fileBytes, readErr := os.ReadFile(mdYamlPath)
@@ -39,6 +81,7 @@ func RunImport(buildPath string, skipVerify bool) error {
 	}
 
 	missing := map[string]any{}
+	ui := map[string]any{}
 	for _, step := range b.Steps {
 		prov := provisioners.NewProvisioner(step.Provisioner)
 		inputs, readProvErr := prov.ReadProvisionerInputs(filepath.Join(buildPath, step.Path))
@@ -46,9 +89,33 @@ func RunImport(buildPath string, skipVerify bool) error {
 			return readProvErr
 		}
 		maps.Copy(missing, provisioners.FindMissingFromMassdriver(inputs, b.CombineParamsConnsMetadata()))
+
+		if uiReader, ok := prov.(provisioners.UIReader); ok {
+			stepUI, readUIErr := uiReader.ReadProvisionerUI(filepath.Join(buildPath, step.Path))
+			if readUIErr != nil {
+				return readUIErr
+			}
+			mergeUIOrder(ui, stepUI)
+		}
 	}
 
-	if !skipVerify {
+	connections := map[string]string{}
+	if opts.MDClient != nil {
+		matched, matchErr := matchConnections(context.Background(), opts.MDClient, missing)
+		if matchErr != nil {
+			// connections are a convenience, so fall back to importing everything as params
+			fmt.Fprintln(status, prettylogs.Orange(fmt.Sprintf("Warning: unable to match variables to resource types, importing them as params: %s", matchErr)))
+		}
+		for _, name := range slices.Sorted(maps.Keys(matched)) {
+			if opts.SkipVerify || promptConfirm(fmt.Sprintf("Import the variable \"%s\" as a connection to %s", name, matched[name])) {
+				connections[name] = matched[name]
+			}
+		}
+	}
+	connectionsRequired := requiredOf(missing, connections)
+	missing = withoutProperties(missing, connections)
+
+	if !opts.SkipVerify {
 		missing = verifyImport(missing)
 	}
 
@@ -56,11 +123,64 @@ func RunImport(buildPath string, skipVerify bool) error {
 	if !missingPropsOk {
 		return errors.New("missing properties is not a map[string]any")
 	}
-	if len(missingProps) == 0 {
-		fmt.Println("No missing parameters found.")
+	if len(missingProps) == 0 && len(connections) == 0 {
+		fmt.Fprintln(status, "No missing parameters found.")
 		return nil
 	}
 
+	changed := []string{}
+	if len(missingProps) > 0 {
+		if paramsErr := addImportedParams(node.Content[0], missing, ui); paramsErr != nil {
+			return paramsErr
+		}
+		changed = append(changed, "params")
+	}
+	if len(connections) > 0 {
+		if connErr := addImportedConnections(node.Content[0], connections, connectionsRequired); connErr != nil {
+			return connErr
+		}
+		changed = append(changed, "connections")
+	}
+	if len(missingProps) > 0 && len(ui) > 0 {
+		if uiErr := addImportedUI(node.Content[0], missingProps, ui); uiErr != nil {
+			return uiErr
+		}
+		changed = append(changed, "ui")
+	}
+
+	newBytes, spliceErr := spliceSections(fileBytes, node.Content[0], changed)
+	if spliceErr != nil {
+		return spliceErr
+	}
+
+	if opts.DryRun || opts.Output == ImportOutputPatch {
+		diff, diffErr := unifiedDiff("massdriver.yaml", fileBytes, newBytes)
+		if diffErr != nil {
+			return diffErr
+		}
+		if opts.Output == ImportOutputPatch {
+			fmt.Fprint(out, diff)
+			return nil
+		}
+		fmt.Fprint(out, prettylogs.Diff(diff))
+		fmt.Fprintln(status, "Dry run: massdriver.yaml was not modified.")
+		return nil
+	}
+
+	// #nosec G306
+	writeErr := os.WriteFile(mdYamlPath, newBytes, 0644)
+	if writeErr != nil {
+		return writeErr
+	}
+
+	fmt.Fprintln(status, "Updated massdriver.yaml with missing parameters.")
+
+	return nil
+}
+
+// addImportedParams appends the missing properties and required names to the params
+// node of massdriver.yaml, creating it if needed, in ui:order where one is suggested.
+func addImportedParams(root *yaml3.Node, missing map[string]any, ui map[string]any) error {
 	var encodedMissing yaml3.Node
 	encodeErr := encodedMissing.Encode(missing)
 	if encodeErr != nil {
@@ -71,10 +191,13 @@ func RunImport(buildPath string, skipVerify bool) error {
 	var paramsNodeValue *yaml3.Node
 	var paramsNodePropertiesNodeValue *yaml3.Node
 	var paramsNodeRequiredNodeValue *yaml3.Node
-	for ii := 0; ii < len(node.Content[0].Content); ii += 2 {
-		iiNodeName := node.Content[0].Content[ii]
+	if mappingValue(root, "params") == nil {
+		root.Content = append(root.Content, &yaml3.Node{Kind: yaml3.ScalarNode, Value: "params"}, &yaml3.Node{Kind: yaml3.MappingNode})
+	}
+	for ii := 0; ii < len(root.Content); ii += 2 {
+		iiNodeName := root.Content[ii]
 		if iiNodeName.Value == "params" {
-			paramsNodeValue = node.Content[0].Content[ii+1]
+			paramsNodeValue = root.Content[ii+1]
 			paramsNodeValue.Style = 0
 			for jj := 0; jj < len(paramsNodeValue.Content); jj += 2 {
 				jjNodeName := paramsNodeValue.Content[jj]
@@ -113,9 +236,11 @@ func RunImport(buildPath string, skipVerify bool) error {
 		if kkNodeName.Value == "properties" {
 			missingPropertiesNodeValue = encodedMissing.Content[kk+1]
 			missingPropertiesNodeValue.Style = 0
+			sortMappingByUIOrder(missingPropertiesNodeValue, ui)
 		}
 		if kkNodeName.Value == "required" {
 			missingRequiredNodeValue = encodedMissing.Content[kk+1]
+			sortSequenceByUIOrder(missingRequiredNodeValue, ui)
 		}
 	}
 
@@ -123,19 +248,6 @@ func RunImport(buildPath string, skipVerify bool) error {
 	paramsNodePropertiesNodeValue.Content = append(paramsNodePropertiesNodeValue.Content, missingPropertiesNodeValue.Content...)
 	paramsNodeRequiredNodeValue.Content = append(paramsNodeRequiredNodeValue.Content, missingRequiredNodeValue.Content...)
 
-	newBytes, marshalErr := yaml3.Marshal(&node)
-	if marshalErr != nil {
-		return marshalErr
-	}
-
-	// #nosec G306
-	writeErr := os.WriteFile(mdYamlPath, newBytes, 0644)
-	if writeErr != nil {
-		return writeErr
-	}
-
-	fmt.Println("Updated massdriver.yaml with missing parameters.")
-
 	return nil
 }
 
@@ -159,26 +271,13 @@ func verifyImport(params map[string]any) map[string]any {
 	}
 
 	for paramName := range missingProperties {
-		prompt := promptui.Prompt{
-			Label:     "Would you like to import the parameter \"" + paramName + "\"",
-			Default:   "y",
-			IsConfirm: true,
-		}
-
-		validate := func(s string) error {
-			//nolint:gocritic // mixed &&/|| precedence is intentional; matches promptui validation pattern
-			if len(s) == 1 && strings.Contains("YyNn", s) || prompt.Default != "" && len(s) == 0 {
-				return nil
-			}
-			return fmt.Errorf("\"%s\" is not a valid response, must be \"y\" or \"n\"", s)
-		}
-		prompt.Validate = validate
-
-		_, err := prompt.Run()
-		confirmed := !errors.Is(err, promptui.ErrAbort)
+		confirmed := promptConfirm("Would you like to import the parameter \"" + paramName + "\"")
 
 		if confirmed {
 			importedProperties[paramName] = missingProperties[paramName]
+			if paramSchema, isMap := missingProperties[paramName].(map[string]any); isMap {
+				importedProperties[paramName] = selectNestedProperties(paramName, paramSchema)
+			}
 			for _, req := range missingRequired {
 				reqStr, reqOk := req.(string)
 				if !reqOk {
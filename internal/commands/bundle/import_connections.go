@@ -0,0 +1,184 @@
+package bundle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/massdriver-cloud/mass/internal/resourcetype"
+	"github.com/massdriver-cloud/massdriver-sdk-go/massdriver"
+	yaml3 "gopkg.in/yaml.v3"
+)
+
+// matchConnections returns the missing object variables that structurally match one of
+// the organization's resource types, mapped to the ID of the best matching type. A type
+// whose name matches the variable's name wins, then the closest structural match.
+// Schemas are fetched and dereferenced lazily: types named like a variable are scored
+// first, and the rest are only fetched when none of those match.
+func matchConnections(ctx context.Context, mdClient *massdriver.Client, missing map[string]any) (map[string]string, error) {
+	props, _ := missing["properties"].(map[string]any)
+	objects := []string{}
+	for name, prop := range props {
+		if propSchema, ok := prop.(map[string]any); ok && propSchema["type"] == "object" {
+			objects = append(objects, name)
+		}
+	}
+	if len(objects) == 0 {
+		return map[string]string{}, nil
+	}
+	slices.Sort(objects)
+
+	resourceTypes, listErr := resourcetype.List(ctx, mdClient)
+	if listErr != nil {
+		return nil, listErr
+	}
+	slices.SortFunc(resourceTypes, func(a, b resourcetype.ResourceType) int { return strings.Compare(a.ID, b.ID) })
+
+	// shared across variables, so each type and each $ref in one is fetched at most once
+	resolver := resourcetype.NewMassdriverResolver(mdClient)
+	derefOpts := resourcetype.DereferenceOptions{Resolver: resolver, Cache: resourcetype.NewRefCache()}
+	schemas := map[string]map[string]any{}
+	typeSchema := func(id string) (map[string]any, error) {
+		if sch, ok := schemas[id]; ok {
+			return sch, nil
+		}
+		rt, getErr := resolver(ctx, id)
+		if getErr != nil {
+			return nil, fmt.Errorf("failed to get resource type %s: %w", id, getErr)
+		}
+		rtSchema, _ := rt["schema"].(map[string]any)
+		dereferenced, derefErr := resourcetype.DereferenceSchema(rtSchema, derefOpts)
+		if derefErr != nil {
+			return nil, fmt.Errorf("failed to dereference resource type %s: %w", id, derefErr)
+		}
+		sch, _ := dereferenced.(map[string]any)
+		schemas[id] = sch
+		return sch, nil
+	}
+
+	matched := map[string]string{}
+	for _, name := range objects {
+		propSchema, _ := props[name].(map[string]any)
+		named, unnamed := []resourcetype.ResourceType{}, []resourcetype.ResourceType{}
+		for _, rt := range resourceTypes {
+			if resourcetype.NameMatches(name, rt.ID) || resourcetype.NameMatches(name, rt.Name) {
+				named = append(named, rt)
+			} else {
+				unnamed = append(unnamed, rt)
+			}
+		}
+
+		bestID, matchErr := bestMatch(propSchema, named, typeSchema)
+		if matchErr != nil {
+			return nil, matchErr
+		}
+		if bestID == "" {
+			if bestID, matchErr = bestMatch(propSchema, unnamed, typeSchema); matchErr != nil {
+				return nil, matchErr
+			}
+		}
+		if bestID != "" {
+			matched[name] = bestID
+		}
+	}
+	return matched, nil
+}
+
+// bestMatch returns the ID of the candidate whose schema sch matches most closely, or
+// an empty string when none match.
+func bestMatch(sch map[string]any, candidates []resourcetype.ResourceType, typeSchema func(id string) (map[string]any, error)) (string, error) {
+	bestID, bestScore := "", 0
+	for _, rt := range candidates {
+		rtSchema, err := typeSchema(rt.ID)
+		if err != nil {
+			return "", err
+		}
+		if score := resourcetype.MatchScore(sch, rtSchema); score > bestScore {
+			bestID, bestScore = rt.ID, score
+		}
+	}
+	return bestID, nil
+}
+
+// requiredOf returns the names in the missing required list that are keys of names.
+func requiredOf(missing map[string]any, names map[string]string) []string {
+	required, _ := missing["required"].([]any)
+	found := []string{}
+	for _, req := range required {
+		if reqStr, ok := req.(string); ok {
+			if _, exists := names[reqStr]; exists {
+				found = append(found, reqStr)
+			}
+		}
+	}
+	return found
+}
+
+// withoutProperties returns a copy of the missing schema without the named properties.
+func withoutProperties(missing map[string]any, names map[string]string) map[string]any {
+	if len(names) == 0 {
+		return missing
+	}
+	props, _ := missing["properties"].(map[string]any)
+	remaining := maps.Clone(props)
+	for name := range names {
+		delete(remaining, name)
+	}
+	required, _ := missing["required"].([]any)
+
+	narrowed := maps.Clone(missing)
+	narrowed["properties"] = remaining
+	narrowed["required"] = slices.DeleteFunc(slices.Clone(required), func(r any) bool {
+		name, _ := r.(string)
+		_, removed := names[name]
+		return removed
+	})
+	return narrowed
+}
+
+// addImportedConnections adds each variable to the massdriver.yaml connections block as
+// a $ref to its resource type.
+func addImportedConnections(root *yaml3.Node, connections map[string]string, required []string) error {
+	connNode := mappingValue(root, "connections")
+	if connNode == nil {
+		connNode = &yaml3.Node{Kind: yaml3.MappingNode}
+		root.Content = append(root.Content, &yaml3.Node{Kind: yaml3.ScalarNode, Value: "connections"}, connNode)
+	}
+	if connNode.Kind != yaml3.MappingNode {
+		return errors.New("connections in massdriver.yaml is not a map")
+	}
+	connNode.Style = 0
+
+	if len(required) > 0 {
+		requiredNode := mappingValue(connNode, "required")
+		if requiredNode == nil {
+			requiredNode = &yaml3.Node{Kind: yaml3.SequenceNode}
+			connNode.Content = append(connNode.Content, &yaml3.Node{Kind: yaml3.ScalarNode, Value: "required"}, requiredNode)
+		}
+		requiredNode.Style = 0
+		for _, name := range required {
+			requiredNode.Content = append(requiredNode.Content, &yaml3.Node{Kind: yaml3.ScalarNode, Value: name})
+		}
+	}
+
+	propsNode := mappingValue(connNode, "properties")
+	if propsNode == nil {
+		propsNode = &yaml3.Node{Kind: yaml3.MappingNode}
+		connNode.Content = append(connNode.Content, &yaml3.Node{Kind: yaml3.ScalarNode, Value: "properties"}, propsNode)
+	}
+	propsNode.Style = 0
+	for _, name := range slices.Sorted(maps.Keys(connections)) {
+		propsNode.Content = append(propsNode.Content,
+			&yaml3.Node{Kind: yaml3.ScalarNode, Value: name},
+			&yaml3.Node{Kind: yaml3.MappingNode, Content: []*yaml3.Node{
+				{Kind: yaml3.ScalarNode, Value: "$ref"},
+				{Kind: yaml3.ScalarNode, Value: connections[name]},
+			}},
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,187 @@
+package bundle
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	yaml3 "gopkg.in/yaml.v3"
+)
+
+// defaultYAMLIndent is used when massdriver.yaml has no indented lines to copy from.
+const defaultYAMLIndent = 2
+
+// spliceSections re-renders the given top-level keys of root and splices them into
+// original in place of their previous text. Every other byte of the file, including
+// comments and formatting in untouched sections, is kept as-is. Keys that weren't in
+// the original file are inserted after the section of the key before them in root, or
+// appended to the end when they come last.
+func spliceSections(original []byte, root *yaml3.Node, keys []string) ([]byte, error) {
+	lines := strings.SplitAfter(string(original), "\n")
+	indent := detectYAMLIndent(lines)
+
+	type section struct {
+		start, end int
+		index      int
+		text       string
+	}
+	sections := []section{}
+	for _, key := range keys {
+		for i := 0; i+1 < len(root.Content); i += 2 {
+			keyNode := root.Content[i]
+			if keyNode.Value != key {
+				continue
+			}
+			text, err := renderSection(keyNode, root.Content[i+1], indent)
+			if err != nil {
+				return nil, err
+			}
+			if keyNode.Line == 0 {
+				at := insertionLine(lines, root, i)
+				sections = append(sections, section{start: at, end: at, index: i, text: text})
+				break
+			}
+			start := keyNode.Line - 1
+			sections = append(sections, section{start: start, end: sectionEnd(lines, start), index: i, text: text})
+			break
+		}
+	}
+
+	// splice from the bottom up so earlier line numbers stay valid, and new keys inserted
+	// on the same line end up in root's order
+	slices.SortStableFunc(sections, func(a, b section) int {
+		if a.start != b.start {
+			return b.start - a.start
+		}
+		return b.index - a.index
+	})
+	for _, s := range sections {
+		if s.start == len(lines) && len(lines) > 0 && !strings.HasSuffix(lines[len(lines)-1], "\n") {
+			lines[len(lines)-1] += "\n"
+		}
+		lines = slices.Replace(lines, s.start, s.end, s.text)
+	}
+
+	return []byte(strings.Join(lines, "")), nil
+}
+
+// insertionLine returns the line index a new top-level key at root.Content[keyIndex]
+// goes on: just past the section of the nearest key before it that's in the file, or the
+// end of the file when no key after it is.
+func insertionLine(lines []string, root *yaml3.Node, keyIndex int) int {
+	last := true
+	for i := keyIndex + 2; i < len(root.Content); i += 2 {
+		if root.Content[i].Line != 0 {
+			last = false
+			break
+		}
+	}
+	if last {
+		return len(lines)
+	}
+	for i := keyIndex - 2; i >= 0; i -= 2 {
+		if line := root.Content[i].Line; line != 0 {
+			return sectionEnd(lines, line-1)
+		}
+	}
+	return 0
+}
+
+// removeSections deletes the text of the given top-level keys from original, as found in
+// root, which must have been parsed from original, along with the comment lines directly
+// above each key. Everything else is kept as-is.
+func removeSections(original []byte, root *yaml3.Node, keys []string) []byte {
+	lines := strings.SplitAfter(string(original), "\n")
+	type section struct{ start, end int }
+	sections := []section{}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		keyNode := root.Content[i]
+		if keyNode.Line == 0 || !slices.Contains(keys, keyNode.Value) {
+			continue
+		}
+		start := keyNode.Line - 1
+		end := sectionEnd(lines, start)
+		// the first key's comment is usually about the whole file, so it's kept
+		for n := strings.Count(keyNode.HeadComment, "\n") + 1; i > 0 && keyNode.HeadComment != "" && n > 0 && strings.HasPrefix(lines[start-1], "#"); n-- {
+			start--
+		}
+		sections = append(sections, section{start: start, end: end})
+	}
+	// remove from the bottom up so earlier line numbers stay valid
+	slices.SortFunc(sections, func(a, b section) int { return b.start - a.start })
+	for _, s := range sections {
+		lines = slices.Delete(lines, s.start, s.end)
+	}
+	return []byte(strings.Join(lines, ""))
+}
+
+// sectionEnd returns the line index just past the value of the top-level key on line
+// start: the last indented line before the next top-level key. Blank lines and unindented
+// comments between the two are left with the file rather than the section.
+func sectionEnd(lines []string, start int) int {
+	end := start + 1
+	for i := start + 1; i < len(lines); i++ {
+		line := lines[i]
+		switch {
+		case strings.TrimSpace(line) == "", strings.HasPrefix(line, "#"):
+			continue
+		case strings.HasPrefix(line, " "), strings.HasPrefix(line, "\t"):
+			end = i + 1
+		default:
+			return end
+		}
+	}
+	return end
+}
+
+// detectYAMLIndent returns the indentation of the first nested line in the file, so
+// re-rendered sections match the rest of massdriver.yaml.
+func detectYAMLIndent(lines []string) int {
+	for i := 1; i < len(lines); i++ {
+		previous := strings.TrimRight(lines[i-1], "\r\n")
+		if strings.HasPrefix(previous, " ") || strings.HasPrefix(previous, "#") || !strings.HasSuffix(previous, ":") {
+			continue
+		}
+		if trimmed := strings.TrimLeft(lines[i], " "); trimmed != lines[i] && strings.TrimSpace(trimmed) != "" {
+			return len(lines[i]) - len(trimmed)
+		}
+	}
+	return defaultYAMLIndent
+}
+
+// renderSection encodes a single top-level key and its value. The key's head and foot
+// comments stay in the original file, outside the spliced range, so they aren't re-emitted.
+func renderSection(key, value *yaml3.Node, indent int) (string, error) {
+	value.FootComment = ""
+	doc := &yaml3.Node{
+		Kind: yaml3.MappingNode,
+		Content: []*yaml3.Node{
+			{Kind: yaml3.ScalarNode, Value: key.Value, LineComment: key.LineComment},
+			value,
+		},
+	}
+
+	var buf bytes.Buffer
+	enc := yaml3.NewEncoder(&buf)
+	enc.SetIndent(indent)
+	if err := enc.Encode(doc); err != nil {
+		return "", err
+	}
+	if err := enc.Close(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// unifiedDiff returns a unified diff between two versions of a file, with git-style
+// a/ and b/ prefixes so it can be applied with `git apply` or `patch -p1`.
+func unifiedDiff(name string, before, after []byte) (string, error) {
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(before)),
+		B:        difflib.SplitLines(string(after)),
+		FromFile: "a/" + name,
+		ToFile:   "b/" + name,
+		Context:  3,
+	})
+}
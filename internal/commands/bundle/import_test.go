@@ -1,11 +1,15 @@
 package bundle_test
 
 import (
+	"bytes"
 	"os"
 	"path"
 	"testing"
 
+	"github.com/massdriver-cloud/mass/internal/api"
 	"github.com/massdriver-cloud/mass/internal/commands/bundle"
+	"github.com/massdriver-cloud/massdriver-sdk-go/massdriver"
+	"github.com/massdriver-cloud/massdriver-sdk-go/massdriver/gql/gqltest"
 )
 
 func TestImportParams(t *testing.T) {
@@ -27,15 +31,15 @@ source_url: github.com/YOUR_NAME_HERE/test-bundle
 access: private
 type: infrastructure
 steps:
-    - path: src
-      provisioner: opentofu
+  - path: src
+    provisioner: opentofu
 params:
-    properties:
-        new:
-            title: new
-            type: string
-    required:
-        - new
+  properties:
+    new:
+      title: new
+      type: string
+  required:
+    - new
 connections: {}
 artifacts: {}
 ui: {}
@@ -76,21 +80,51 @@ source_url: github.com/YOUR_NAME_HERE/test-bundle
 access: private
 type: infrastructure
 steps:
-    - path: src
-      provisioner: opentofu
+  - path: src
+    provisioner: opentofu
 params:
-    properties:
-        foo:
-            type: string
-        new:
-            title: new
-            type: string
-    required:
-        - foo
-        - new
+  properties:
+    foo:
+      type: string
+    new:
+      title: new
+      type: string
+  required:
+    - foo
+    - new
 connections: {}
 artifacts: {}
 ui: {}
+`,
+		},
+		{
+			name:       "comments-kept",
+			mdyamlPath: "testdata/commented-massdriver.yaml",
+			tfContent:  `variable "new" {type = string}`,
+			want: `# The bundle's identity
+schema: draft-07
+name: "test-bundle"   # keep the quotes
+description: "Bundles to test things"
+source_url: github.com/YOUR_NAME_HERE/test-bundle
+steps:
+- path: src
+  provisioner: opentofu
+
+# Params are imported from src
+params:
+  properties:
+    foo: {type: string}
+    new:
+      title: new
+      type: string
+  # foo is required
+  required: [foo, new]
+
+# schema-connections.json
+connections:
+  properties: {}
+artifacts: {properties: {}}
+ui: {}
 `,
 		},
 	}
@@ -120,7 +154,7 @@ ui: {}
 			}
 
 			// Run the ImportParams function
-			err = bundle.RunImport(testDir, true)
+			err = bundle.RunImport(testDir, bundle.ImportOptions{SkipVerify: true})
 			if err != nil {
 				t.Fatalf("ImportParams returned an error: %v", err)
 			}
@@ -138,3 +172,334 @@ ui: {}
 		})
 	}
 }
+
+func TestImportHelmParams(t *testing.T) {
+	values := `replicas: 1
+# -- Image to deploy
+image:
+  # -- Image tag
+  tag: latest
+  repository: nginx
+# -- Port the service listens on
+port: 8080
+`
+	want := `schema: draft-07
+name: "test-bundle"
+description: "Bundles to test things"
+source_url: github.com/YOUR_NAME_HERE/test-bundle
+access: private
+type: infrastructure
+steps:
+  - path: chart
+    provisioner: helm
+params:
+  properties:
+    replicas:
+      type: integer
+    image:
+      description: Image to deploy
+      properties:
+        tag:
+          default: latest
+          description: Image tag
+          title: tag
+          type: string
+        repository:
+          default: nginx
+          title: repository
+          type: string
+      required:
+        - tag
+        - repository
+      title: image
+      type: object
+    port:
+      default: 8080
+      description: Port the service listens on
+      title: port
+      type: integer
+  required:
+    - replicas
+    - image
+    - port
+connections: {}
+artifacts: {}
+ui:
+  ui:order:
+    - replicas
+    - image
+    - port
+    - "*"
+  image:
+    ui:order:
+      - tag
+      - repository
+`
+
+	testDir := t.TempDir()
+	mdYamlContent, err := os.ReadFile("testdata/helm-massdriver.yaml")
+	if err != nil {
+		t.Fatalf("Failed to read massdriver.yaml file: %v", err)
+	}
+	if err = os.WriteFile(path.Join(testDir, "massdriver.yaml"), mdYamlContent, 0644); err != nil {
+		t.Fatalf("Failed to write massdriver.yaml file: %v", err)
+	}
+	if err = os.MkdirAll(path.Join(testDir, "chart"), 0755); err != nil {
+		t.Fatalf("Failed to create chart directory: %v", err)
+	}
+	if err = os.WriteFile(path.Join(testDir, "chart", "values.yaml"), []byte(values), 0644); err != nil {
+		t.Fatalf("Failed to write values.yaml file: %v", err)
+	}
+
+	if err = bundle.RunImport(testDir, bundle.ImportOptions{SkipVerify: true}); err != nil {
+		t.Fatalf("ImportParams returned an error: %v", err)
+	}
+
+	got, err := os.ReadFile(path.Join(testDir, "massdriver.yaml"))
+	if err != nil {
+		t.Fatalf("Failed to read updated massdriver.yaml: %v", err)
+	}
+	if string(got) != want {
+		t.Errorf("Updated massdriver.yaml content does not match expected content.\nWant:\n%s\nGot:\n%s", want, string(got))
+	}
+}
+
+func TestImportDryRun(t *testing.T) {
+	type test struct {
+		name   string
+		output string
+		dryRun bool
+	}
+	tests := []test{
+		{name: "dry-run", output: bundle.ImportOutputFile, dryRun: true},
+		{name: "patch", output: bundle.ImportOutputPatch},
+	}
+
+	want := `--- a/massdriver.yaml
++++ b/massdriver.yaml
+@@ -11,8 +11,11 @@
+ params:
+   properties:
+     foo: {type: string}
++    new:
++      title: new
++      type: string
+   # foo is required
+-  required: [foo]
++  required: [foo, new]
+ 
+ # schema-connections.json
+ connections:
+`
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			testDir := t.TempDir()
+			mdYamlContent, err := os.ReadFile("testdata/commented-massdriver.yaml")
+			if err != nil {
+				t.Fatalf("Failed to read massdriver.yaml file: %v", err)
+			}
+			if err = os.WriteFile(path.Join(testDir, "massdriver.yaml"), mdYamlContent, 0644); err != nil {
+				t.Fatalf("Failed to write massdriver.yaml file: %v", err)
+			}
+			if err = os.MkdirAll(path.Join(testDir, "src"), 0755); err != nil {
+				t.Fatalf("Failed to create src directory: %v", err)
+			}
+			if err = os.WriteFile(path.Join(testDir, "src", "main.tf"), []byte(`variable "new" {type = string}`), 0644); err != nil {
+				t.Fatalf("Failed to write main.tf file: %v", err)
+			}
+
+			var out bytes.Buffer
+			err = bundle.RunImport(testDir, bundle.ImportOptions{SkipVerify: true, DryRun: tc.dryRun, Output: tc.output, Out: &out})
+			if err != nil {
+				t.Fatalf("RunImport returned an error: %v", err)
+			}
+
+			if out.String() != want {
+				t.Errorf("got diff:\n%s\nwant:\n%s", out.String(), want)
+			}
+
+			got, err := os.ReadFile(path.Join(testDir, "massdriver.yaml"))
+			if err != nil {
+				t.Fatalf("Failed to read massdriver.yaml: %v", err)
+			}
+			if !bytes.Equal(got, mdYamlContent) {
+				t.Errorf("massdriver.yaml was modified:\n%s", got)
+			}
+		})
+	}
+}
+
+func TestImportConnections(t *testing.T) {
+	tfContent := `variable "network" {
+  type = object({
+    id   = string
+    cidr = string
+  })
+}
+variable "new" {type = string}
+`
+	want := `schema: draft-07
+name: "test-bundle"
+description: "Bundles to test things"
+source_url: github.com/YOUR_NAME_HERE/test-bundle
+access: private
+type: infrastructure
+steps:
+  - path: src
+    provisioner: opentofu
+params:
+  properties:
+    foo:
+      type: string
+    new:
+      title: new
+      type: string
+  required:
+    - foo
+    - new
+connections:
+  required:
+    - network
+  properties:
+    network:
+      $ref: massdriver/network
+artifacts: {}
+ui: {}
+`
+
+	stringProp := map[string]any{"type": "string"}
+	mock := gqltest.NewClient(
+		gqltest.RespondWithData(map[string]any{
+			"resourceTypes": map[string]any{
+				"items": []map[string]any{
+					{"id": "massdriver/bucket", "name": "massdriver/bucket"},
+					{"id": "massdriver/network", "name": "massdriver/network"},
+				},
+				"cursor": map[string]any{"next": "", "previous": ""},
+			},
+		}),
+		// only the type named like the variable is fetched, since it matches
+		gqltest.RespondWithData(map[string]any{
+			"resourceType": map[string]any{
+				"id":     "massdriver/network",
+				"name":   "massdriver/network",
+				"schema": map[string]any{"properties": map[string]any{"id": stringProp, "cidr": stringProp, "region": stringProp}},
+			},
+		}),
+	)
+	t.Cleanup(api.SetTransportForTest(mock))
+	mdClient, err := massdriver.NewClient(
+		massdriver.WithGQLClient(mock),
+		massdriver.WithOrganizationID("test-org"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testDir := t.TempDir()
+	mdYamlContent, err := os.ReadFile("testdata/foo-massdriver.yaml")
+	if err != nil {
+		t.Fatalf("Failed to read massdriver.yaml file: %v", err)
+	}
+	if err = os.WriteFile(path.Join(testDir, "massdriver.yaml"), mdYamlContent, 0644); err != nil {
+		t.Fatalf("Failed to write massdriver.yaml file: %v", err)
+	}
+	if err = os.MkdirAll(path.Join(testDir, "src"), 0755); err != nil {
+		t.Fatalf("Failed to create src directory: %v", err)
+	}
+	if err = os.WriteFile(path.Join(testDir, "src", "main.tf"), []byte(tfContent), 0644); err != nil {
+		t.Fatalf("Failed to write main.tf file: %v", err)
+	}
+
+	if err = bundle.RunImport(testDir, bundle.ImportOptions{SkipVerify: true, MDClient: mdClient}); err != nil {
+		t.Fatalf("RunImport returned an error: %v", err)
+	}
+
+	got, err := os.ReadFile(path.Join(testDir, "massdriver.yaml"))
+	if err != nil {
+		t.Fatalf("Failed to read updated massdriver.yaml: %v", err)
+	}
+	if string(got) != want {
+		t.Errorf("Updated massdriver.yaml content does not match expected content.\nWant:\n%s\nGot:\n%s", want, string(got))
+	}
+	if mock.Pending() != 0 {
+		t.Errorf("expected every resource type response to be used, %d left", mock.Pending())
+	}
+}
+
+func TestImportConnectionsLookupFails(t *testing.T) {
+	tfContent := `variable "network" {
+  type = object({
+    id = string
+  })
+}
+`
+	want := `schema: draft-07
+name: "test-bundle"
+description: "Bundles to test things"
+source_url: github.com/YOUR_NAME_HERE/test-bundle
+access: private
+type: infrastructure
+steps:
+  - path: src
+    provisioner: opentofu
+params:
+  properties:
+    foo:
+      type: string
+    network:
+      properties:
+        id:
+          title: id
+          type: string
+      required:
+        - id
+      title: network
+      type: object
+  required:
+    - foo
+    - network
+connections: {}
+artifacts: {}
+ui: {}
+`
+
+	// a malformed response stands in for a network or auth failure
+	mock := gqltest.NewClient(gqltest.RespondWithData(map[string]any{"resourceTypes": "unavailable"}))
+	t.Cleanup(api.SetTransportForTest(mock))
+	mdClient, err := massdriver.NewClient(
+		massdriver.WithGQLClient(mock),
+		massdriver.WithOrganizationID("test-org"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testDir := t.TempDir()
+	mdYamlContent, err := os.ReadFile("testdata/foo-massdriver.yaml")
+	if err != nil {
+		t.Fatalf("Failed to read massdriver.yaml file: %v", err)
+	}
+	if err = os.WriteFile(path.Join(testDir, "massdriver.yaml"), mdYamlContent, 0644); err != nil {
+		t.Fatalf("Failed to write massdriver.yaml file: %v", err)
+	}
+	if err = os.MkdirAll(path.Join(testDir, "src"), 0755); err != nil {
+		t.Fatalf("Failed to create src directory: %v", err)
+	}
+	if err = os.WriteFile(path.Join(testDir, "src", "main.tf"), []byte(tfContent), 0644); err != nil {
+		t.Fatalf("Failed to write main.tf file: %v", err)
+	}
+
+	if err = bundle.RunImport(testDir, bundle.ImportOptions{SkipVerify: true, MDClient: mdClient}); err != nil {
+		t.Fatalf("RunImport returned an error: %v", err)
+	}
+
+	got, err := os.ReadFile(path.Join(testDir, "massdriver.yaml"))
+	if err != nil {
+		t.Fatalf("Failed to read updated massdriver.yaml: %v", err)
+	}
+	if string(got) != want {
+		t.Errorf("Updated massdriver.yaml content does not match expected content.\nWant:\n%s\nGot:\n%s", want, string(got))
+	}
+}
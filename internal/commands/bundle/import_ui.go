@@ -0,0 +1,221 @@
+package bundle
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+	yaml3 "gopkg.in/yaml.v3"
+)
+
+// promptConfirm asks a yes/no question, defaulting to yes.
+func promptConfirm(label string) bool {
+	prompt := promptui.Prompt{
+		Label:     label,
+		Default:   "y",
+		IsConfirm: true,
+	}
+
+	validate := func(s string) error {
+		//nolint:gocritic // mixed &&/|| precedence is intentional; matches promptui validation pattern
+		if len(s) == 1 && strings.Contains("YyNn", s) || prompt.Default != "" && len(s) == 0 {
+			return nil
+		}
+		return fmt.Errorf("\"%s\" is not a valid response, must be \"y\" or \"n\"", s)
+	}
+	prompt.Validate = validate
+
+	_, err := prompt.Run()
+	return !errors.Is(err, promptui.ErrAbort)
+}
+
+// selectNestedProperties offers to import only some of the nested keys of an object
+// param, rather than its whole subtree. Returns a copy of sch narrowed to the chosen keys.
+func selectNestedProperties(path string, sch map[string]any) map[string]any {
+	props, ok := sch["properties"].(map[string]any)
+	if !ok || len(props) < 2 {
+		return sch
+	}
+	if promptConfirm(fmt.Sprintf("Import every nested key of \"%s\"", path)) {
+		return sch
+	}
+
+	names := slices.Sorted(maps.Keys(props))
+	selected := map[string]any{}
+	for _, name := range names {
+		if !promptConfirm(fmt.Sprintf("Import the nested key \"%s.%s\"", path, name)) {
+			continue
+		}
+		selected[name] = props[name]
+		if nested, isMap := props[name].(map[string]any); isMap {
+			selected[name] = selectNestedProperties(path+"."+name, nested)
+		}
+	}
+
+	narrowed := maps.Clone(sch)
+	narrowed["properties"] = selected
+	if required, hasRequired := sch["required"].([]any); hasRequired {
+		narrowed["required"] = slices.DeleteFunc(slices.Clone(required), func(r any) bool {
+			name, _ := r.(string)
+			_, kept := selected[name]
+			return !kept
+		})
+	}
+	return narrowed
+}
+
+// mergeUIOrder merges the ui:order entries of src into dst, keeping the first order seen
+// for each level.
+func mergeUIOrder(dst, src map[string]any) {
+	for key, value := range src {
+		existing, exists := dst[key]
+		if !exists {
+			dst[key] = value
+			continue
+		}
+		existingMap, existingOk := existing.(map[string]any)
+		valueMap, valueOk := value.(map[string]any)
+		if existingOk && valueOk {
+			mergeUIOrder(existingMap, valueMap)
+		}
+	}
+}
+
+// uiOrderIndex returns the position of name in a ui:order list, or len(order) if absent.
+func uiOrderIndex(ui map[string]any, name string) int {
+	order, _ := ui["ui:order"].([]any)
+	if idx := slices.Index(order, any(name)); idx != -1 {
+		return idx
+	}
+	return len(order)
+}
+
+// sortMappingByUIOrder reorders the key/value pairs of a mapping node, and of its nested
+// properties mappings, to follow ui:order. Keys absent from the order keep their place
+// after the ordered ones.
+func sortMappingByUIOrder(node *yaml3.Node, ui map[string]any) {
+	if node == nil || node.Kind != yaml3.MappingNode || ui == nil {
+		return
+	}
+
+	type pair struct{ key, value *yaml3.Node }
+	pairs := make([]pair, 0, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		pairs = append(pairs, pair{node.Content[i], node.Content[i+1]})
+	}
+	slices.SortStableFunc(pairs, func(a, b pair) int {
+		return uiOrderIndex(ui, a.key.Value) - uiOrderIndex(ui, b.key.Value)
+	})
+
+	node.Content = node.Content[:0]
+	for _, p := range pairs {
+		node.Content = append(node.Content, p.key, p.value)
+		nestedUI, _ := ui[p.key.Value].(map[string]any)
+		if props := mappingValue(p.value, "properties"); props != nil {
+			sortMappingByUIOrder(props, nestedUI)
+		}
+	}
+}
+
+// sortSequenceByUIOrder reorders the scalars of a sequence node to follow ui:order.
+func sortSequenceByUIOrder(node *yaml3.Node, ui map[string]any) {
+	if node == nil || node.Kind != yaml3.SequenceNode || ui == nil {
+		return
+	}
+	slices.SortStableFunc(node.Content, func(a, b *yaml3.Node) int {
+		return uiOrderIndex(ui, a.Value) - uiOrderIndex(ui, b.Value)
+	})
+}
+
+// mappingValue returns the value node for key in a mapping node, or nil.
+func mappingValue(node *yaml3.Node, key string) *yaml3.Node {
+	if node == nil || node.Kind != yaml3.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// addImportedUI adds the imported params to the massdriver.yaml ui block: their names are
+// added to ui:order, in the suggested order, ahead of any "*" wildcard, and imported object
+// params without UI settings of their own get the suggested nested order.
+func addImportedUI(root *yaml3.Node, imported map[string]any, ui map[string]any) error {
+	uiNode := mappingValue(root, "ui")
+	if uiNode == nil {
+		uiNode = &yaml3.Node{Kind: yaml3.MappingNode}
+		root.Content = append(root.Content, &yaml3.Node{Kind: yaml3.ScalarNode, Value: "ui"}, uiNode)
+	}
+	if uiNode.Kind != yaml3.MappingNode {
+		return errors.New("ui in massdriver.yaml is not a map")
+	}
+	uiNode.Style = 0
+
+	names := slices.SortedStableFunc(maps.Keys(imported), func(a, b string) int {
+		if diff := uiOrderIndex(ui, a) - uiOrderIndex(ui, b); diff != 0 {
+			return diff
+		}
+		return strings.Compare(a, b)
+	})
+
+	orderNode := mappingValue(uiNode, "ui:order")
+	if orderNode == nil {
+		orderNode = &yaml3.Node{Kind: yaml3.SequenceNode, Content: []*yaml3.Node{{Kind: yaml3.ScalarNode, Value: "*"}}}
+		uiNode.Content = append(uiNode.Content, &yaml3.Node{Kind: yaml3.ScalarNode, Value: "ui:order"}, orderNode)
+	}
+	orderNode.Style = 0
+	insertAt := slices.IndexFunc(orderNode.Content, func(n *yaml3.Node) bool { return n.Value == "*" })
+	if insertAt == -1 {
+		insertAt = len(orderNode.Content)
+	}
+	newEntries := []*yaml3.Node{}
+	for _, name := range names {
+		newEntries = append(newEntries, &yaml3.Node{Kind: yaml3.ScalarNode, Value: name})
+	}
+	orderNode.Content = slices.Insert(orderNode.Content, insertAt, newEntries...)
+
+	for _, name := range names {
+		nestedUI, hasNested := ui[name].(map[string]any)
+		paramSchema, isMap := imported[name].(map[string]any)
+		if !hasNested || !isMap || mappingValue(uiNode, name) != nil {
+			continue
+		}
+		var nestedNode yaml3.Node
+		if err := nestedNode.Encode(uiForSchema(nestedUI, paramSchema)); err != nil {
+			return err
+		}
+		uiNode.Content = append(uiNode.Content, &yaml3.Node{Kind: yaml3.ScalarNode, Value: name}, &nestedNode)
+	}
+
+	return nil
+}
+
+// uiForSchema narrows a suggested UI schema to the properties sch actually declares, since
+// RJSF rejects a ui:order naming properties that don't exist.
+func uiForSchema(ui map[string]any, sch map[string]any) map[string]any {
+	props, _ := sch["properties"].(map[string]any)
+	narrowed := map[string]any{}
+
+	if order, ok := ui["ui:order"].([]any); ok {
+		narrowed["ui:order"] = slices.DeleteFunc(slices.Clone(order), func(name any) bool {
+			nameStr, _ := name.(string)
+			_, exists := props[nameStr]
+			return !exists
+		})
+	}
+	for name, prop := range props {
+		nestedUI, hasNested := ui[name].(map[string]any)
+		propSchema, isMap := prop.(map[string]any)
+		if hasNested && isMap {
+			narrowed[name] = uiForSchema(nestedUI, propSchema)
+		}
+	}
+
+	return narrowed
+}
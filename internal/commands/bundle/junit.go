@@ -0,0 +1,74 @@
+package bundle
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// junitTestSuites is the root of a JUnit XML report, as read by most CI systems.
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnit writes test results to path as a JUnit XML report with one suite for the bundle.
+func writeJUnit(path string, suiteName string, results []testResult, elapsed time.Duration) error {
+	suite := junitTestSuite{
+		Name:  suiteName,
+		Tests: len(results),
+		Time:  formatJUnitTime(elapsed),
+	}
+	for _, result := range results {
+		testCase := junitTestCase{
+			Name:      result.name,
+			Classname: suiteName,
+			Time:      formatJUnitTime(result.elapsed),
+		}
+		if len(result.failures) > 0 {
+			suite.Failures++
+			testCase.Failure = &junitFailure{
+				Message: fmt.Sprintf("%d check(s) failed", len(result.failures)),
+				Text:    strings.Join(result.failures, "\n"),
+			}
+		}
+		suite.Cases = append(suite.Cases, testCase)
+	}
+
+	data, err := xml.MarshalIndent(junitTestSuites{Suites: []junitTestSuite{suite}}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JUnit report: %w", err)
+	}
+	data = append([]byte(xml.Header), data...)
+	data = append(data, '\n')
+	if err = os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write JUnit report: %w", err)
+	}
+	return nil
+}
+
+func formatJUnitTime(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
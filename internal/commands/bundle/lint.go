@@ -9,31 +9,34 @@ import (
 	"github.com/massdriver-cloud/massdriver-sdk-go/massdriver"
 )
 
+// lintCheck is the result of one named lint check.
+type lintCheck struct {
+	name   string
+	result bundle.LintResult
+}
+
+// lintChecks runs every lint check on the bundle, in the order they are reported.
+func lintChecks(b *bundle.Bundle, mdClient *massdriver.Client) []lintCheck {
+	return []lintCheck{
+		{name: "Schema validation", result: b.LintSchema(mdClient.Config().URL)},
+		{name: "Parameter and connection collision", result: b.LintParamsConnectionsNameCollision()},
+		{name: "Required parameters", result: b.LintMatchRequired()},
+		{name: "Inputs match provisioner", result: b.LintInputsMatchProvisioner()},
+		{name: "Step config", result: b.LintStepConfig()},
+		{name: "Step inputs", result: b.LintStepInputs()},
+		{name: "App configuration", result: b.LintAppSpec()},
+	}
+}
+
 // RunLint runs all lint checks on the bundle and returns the combined result.
 func RunLint(b *bundle.Bundle, mdClient *massdriver.Client) bundle.LintResult {
 	fmt.Println("Checking massdriver.yaml for errors...")
 
 	var allResults bundle.LintResult
-
-	// Schema validation
-	schemaResult := b.LintSchema(mdClient.Config().URL)
-	allResults.Merge(schemaResult)
-	printLintResult("Schema validation", schemaResult)
-
-	// Parameter and connection collision check
-	collisionResult := b.LintParamsConnectionsNameCollision()
-	allResults.Merge(collisionResult)
-	printLintResult("Parameter and connection collision", collisionResult)
-
-	// Required parameters check
-	requiredResult := b.LintMatchRequired()
-	allResults.Merge(requiredResult)
-	printLintResult("Required parameters", requiredResult)
-
-	// Inputs match provisioner check
-	inputsResult := b.LintInputsMatchProvisioner()
-	allResults.Merge(inputsResult)
-	printLintResult("Inputs match provisioner", inputsResult)
+	for _, check := range lintChecks(b, mdClient) {
+		allResults.Merge(check.result)
+		printLintResult(check.name, check.result)
+	}
 
 	return allResults
 }
@@ -0,0 +1,177 @@
+package bundle
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/massdriver-cloud/mass/internal/bundle"
+	"github.com/massdriver-cloud/mass/internal/prettylogs"
+	yaml3 "gopkg.in/yaml.v3"
+)
+
+// DefaultMigrateVersion is the version given to bundles that don't declare one.
+const DefaultMigrateVersion = "0.0.1"
+
+// deprecatedFields are top-level massdriver.yaml keys the platform no longer reads.
+var deprecatedFields = []string{"access", "type"}
+
+// MigrateOptions controls RunMigrate.
+type MigrateOptions struct {
+	// Version is set on bundles without a version. Defaults to DefaultMigrateVersion.
+	Version string
+	// DryRun prints a diff of the migrated massdriver.yaml instead of writing it.
+	DryRun bool
+	// Out receives the summary and diff. Defaults to stdout.
+	Out io.Writer
+}
+
+// MigrateEdit is one change made to massdriver.yaml by a migration.
+type MigrateEdit struct {
+	Field       string
+	Description string
+}
+
+// RunMigrate rewrites a bundle's massdriver.yaml to drop the legacy fields that
+// bundle.Unmarshal warns about: deprecated fields are removed, an initial version is
+// set and the implicit terraform step is written out. Only the affected sections are
+// rewritten; comments and formatting elsewhere in the file are kept. It prints a
+// summary of every edit and returns them.
+func RunMigrate(buildPath string, opts MigrateOptions) ([]MigrateEdit, error) {
+	if opts.Version == "" {
+		opts.Version = DefaultMigrateVersion
+	}
+	if opts.Out == nil {
+		opts.Out = os.Stdout
+	}
+	if !bundle.IsValidVersion(opts.Version) {
+		return nil, fmt.Errorf("invalid version %s, must follow semantic versioning (MAJOR.MINOR.PATCH), e.g. 1.2.3", opts.Version)
+	}
+
+	mdYamlPath := filepath.Join(buildPath, "massdriver.yaml")
+	original, err := os.ReadFile(mdYamlPath)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := parseMassdriverYAML(original)
+	if err != nil {
+		return nil, err
+	}
+	edits := []MigrateEdit{}
+	removed := []string{}
+	for _, field := range deprecatedFields {
+		if value := mappingValue(root, field); value != nil {
+			removed = append(removed, field)
+			edits = append(edits, MigrateEdit{Field: field, Description: fmt.Sprintf("removed the deprecated %s field (was %q)", field, value.Value)})
+		}
+	}
+	migrated := removeSections(original, root, removed)
+
+	// the file is parsed again so the remaining sections have the line numbers of the
+	// file they're spliced into
+	if root, err = parseMassdriverYAML(migrated); err != nil {
+		return nil, err
+	}
+	changed := []string{}
+	if version := mappingValue(root, "version"); version == nil || version.Value == "" {
+		setTopLevelField(root, "version", &yaml3.Node{Kind: yaml3.ScalarNode, Value: opts.Version}, "description", "name")
+		changed = append(changed, "version")
+		edits = append(edits, MigrateEdit{Field: "version", Description: "set the initial version to " + opts.Version})
+	}
+	if steps := mappingValue(root, "steps"); steps == nil || len(steps.Content) == 0 {
+		setTopLevelField(root, "steps", defaultStepsNode(), "version", "source_url", "description", "name")
+		changed = append(changed, "steps")
+		edits = append(edits, MigrateEdit{Field: "steps", Description: "added the implicit src step with the terraform provisioner"})
+	}
+	if migrated, err = spliceSections(migrated, root, changed); err != nil {
+		return nil, err
+	}
+
+	if len(edits) == 0 {
+		fmt.Fprintln(opts.Out, "massdriver.yaml is up to date, nothing to migrate.")
+		return edits, nil
+	}
+
+	if opts.DryRun {
+		diff, diffErr := unifiedDiff("massdriver.yaml", original, migrated)
+		if diffErr != nil {
+			return nil, diffErr
+		}
+		fmt.Fprint(opts.Out, prettylogs.Diff(diff))
+	} else {
+		// #nosec G306
+		if writeErr := os.WriteFile(mdYamlPath, migrated, 0644); writeErr != nil {
+			return nil, writeErr
+		}
+	}
+
+	for _, edit := range edits {
+		fmt.Fprintf(opts.Out, "%s %s: %s\n", prettylogs.Green("✓"), edit.Field, edit.Description)
+	}
+	if opts.DryRun {
+		fmt.Fprintln(opts.Out, "Dry run: massdriver.yaml was not modified.")
+	} else {
+		fmt.Fprintf(opts.Out, "Migrated massdriver.yaml with %d edit(s).\n", len(edits))
+	}
+	return edits, nil
+}
+
+// parseMassdriverYAML parses massdriver.yaml into the node of its top-level mapping.
+func parseMassdriverYAML(data []byte) (*yaml3.Node, error) {
+	var doc yaml3.Node
+	if err := yaml3.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml3.MappingNode {
+		return nil, errors.New("massdriver.yaml must be a mapping")
+	}
+	return doc.Content[0], nil
+}
+
+// setTopLevelField sets key to value in root. A key that isn't in root yet is added
+// after the first of the given keys that is, or at the end.
+func setTopLevelField(root *yaml3.Node, key string, value *yaml3.Node, after ...string) {
+	if i := mappingKeyIndex(root, key); i != -1 {
+		root.Content[i+1] = value
+		return
+	}
+
+	at := len(root.Content)
+	for _, previous := range after {
+		if i := mappingKeyIndex(root, previous); i != -1 {
+			at = i + 2
+			break
+		}
+	}
+	root.Content = slices.Insert(root.Content, at, &yaml3.Node{Kind: yaml3.ScalarNode, Value: key}, value)
+}
+
+// mappingKeyIndex returns the index of key's node in the Content of a mapping node, or -1.
+func mappingKeyIndex(node *yaml3.Node, key string) int {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// defaultStepsNode is the step bundle.Unmarshal assumes when massdriver.yaml has none.
+func defaultStepsNode() *yaml3.Node {
+	return &yaml3.Node{
+		Kind: yaml3.SequenceNode,
+		Content: []*yaml3.Node{{
+			Kind: yaml3.MappingNode,
+			Content: []*yaml3.Node{
+				{Kind: yaml3.ScalarNode, Value: "path"},
+				{Kind: yaml3.ScalarNode, Value: "src"},
+				{Kind: yaml3.ScalarNode, Value: "provisioner"},
+				{Kind: yaml3.ScalarNode, Value: "terraform"},
+			},
+		}},
+	}
+}
@@ -0,0 +1,158 @@
+package bundle_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/massdriver-cloud/mass/internal/commands/bundle"
+)
+
+func TestRunMigrate(t *testing.T) {
+	type test struct {
+		name      string
+		mdyaml    string
+		opts      bundle.MigrateOptions
+		want      string
+		wantEdits []string
+	}
+	tests := []test{
+		{
+			name: "legacy fields",
+			mdyaml: `# Postgres bundle
+schema: draft-07
+name: postgres
+description: A postgres database
+# access is no longer used
+access: private
+type: infrastructure
+
+params:
+  # the instance size
+  properties:
+    size:
+      type: string
+connections: {}
+`,
+			want: `# Postgres bundle
+schema: draft-07
+name: postgres
+description: A postgres database
+version: 0.0.1
+steps:
+  - path: src
+    provisioner: terraform
+
+params:
+  # the instance size
+  properties:
+    size:
+      type: string
+connections: {}
+`,
+			wantEdits: []string{"access", "type", "version", "steps"},
+		},
+		{
+			name: "empty version and steps",
+			mdyaml: `name: postgres
+version: ""
+steps: []
+params: {}
+`,
+			opts: bundle.MigrateOptions{Version: "1.0.0"},
+			want: `name: postgres
+version: 1.0.0
+steps:
+  - path: src
+    provisioner: terraform
+params: {}
+`,
+			wantEdits: []string{"version", "steps"},
+		},
+		{
+			name: "up to date",
+			mdyaml: `name: postgres
+version: 1.2.3
+steps:
+  - path: src
+    provisioner: opentofu # comment
+`,
+			want: `name: postgres
+version: 1.2.3
+steps:
+  - path: src
+    provisioner: opentofu # comment
+`,
+			wantEdits: []string{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			mdYamlPath := filepath.Join(dir, "massdriver.yaml")
+			if err := os.WriteFile(mdYamlPath, []byte(tc.mdyaml), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			var out bytes.Buffer
+			tc.opts.Out = &out
+			edits, err := bundle.RunMigrate(dir, tc.opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got, err := os.ReadFile(mdYamlPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tc.want {
+				t.Errorf("got massdriver.yaml:\n%s\nwant:\n%s", got, tc.want)
+			}
+
+			fields := []string{}
+			for _, edit := range edits {
+				fields = append(fields, edit.Field)
+				if !strings.Contains(out.String(), edit.Description) {
+					t.Errorf("summary is missing %q:\n%s", edit.Description, out.String())
+				}
+			}
+			if !reflect.DeepEqual(fields, tc.wantEdits) {
+				t.Errorf("got edits %v, want %v", fields, tc.wantEdits)
+			}
+
+			// migrating again changes nothing
+			again, err := bundle.RunMigrate(dir, bundle.MigrateOptions{Out: &out})
+			if err != nil || len(again) != 0 {
+				t.Errorf("second migration made edits %v, err %v", again, err)
+			}
+		})
+	}
+}
+
+func TestRunMigrateDryRun(t *testing.T) {
+	dir := t.TempDir()
+	mdyaml := "name: postgres\naccess: private\nversion: 1.0.0\nsteps:\n  - path: src\n    provisioner: opentofu\n"
+	mdYamlPath := filepath.Join(dir, "massdriver.yaml")
+	if err := os.WriteFile(mdYamlPath, []byte(mdyaml), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if _, err := bundle.RunMigrate(dir, bundle.MigrateOptions{DryRun: true, Out: &out}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "access: private") || !strings.Contains(out.String(), "Dry run") {
+		t.Errorf("expected a diff removing access, got:\n%s", out.String())
+	}
+	got, err := os.ReadFile(mdYamlPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != mdyaml {
+		t.Errorf("dry run modified massdriver.yaml:\n%s", got)
+	}
+}
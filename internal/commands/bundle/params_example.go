@@ -0,0 +1,124 @@
+package bundle
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/massdriver-cloud/mass/internal/bundle"
+	"github.com/massdriver-cloud/mass/internal/files"
+	"github.com/massdriver-cloud/mass/internal/params"
+	"github.com/massdriver-cloud/mass/internal/resourcetype"
+	"github.com/massdriver-cloud/massdriver-sdk-go/massdriver"
+)
+
+// ParamsExampleFormats are the output formats supported by RunParamsExample.
+var ParamsExampleFormats = []string{"json", "yaml", "tfvars"}
+
+// ParamsExampleOptions controls RunParamsExample.
+type ParamsExampleOptions struct {
+	// Format is one of ParamsExampleFormats. Defaults to json.
+	Format string
+	// RequiredOnly leaves out every param the schema doesn't require.
+	RequiredOnly bool
+	// Out receives the example. Defaults to stdout.
+	Out io.Writer
+}
+
+// RunParamsExample writes an example params document for a bundle. source is either a
+// local bundle directory or a published bundle as <name>@<version>, where the version
+// may also be a release channel. Local bundles are dereferenced the same way
+// `mass bundle build` does, so the example matches what the bundle would publish.
+func RunParamsExample(ctx context.Context, mdClient *massdriver.Client, source string, opts ParamsExampleOptions) error {
+	if opts.Format == "" {
+		opts.Format = "json"
+	}
+	if opts.Out == nil {
+		opts.Out = os.Stdout
+	}
+
+	var sch map[string]any
+	var err error
+	if isLocalBundle(source) {
+		sch, err = localParamsSchema(source, mdClient)
+	} else {
+		sch, err = publishedParamsSchema(ctx, mdClient, source)
+	}
+	if err != nil {
+		return err
+	}
+
+	data, err := files.Marshal("."+opts.Format, params.Example(sch, params.ExampleOptions{RequiredOnly: opts.RequiredOnly}))
+	if err != nil {
+		return fmt.Errorf("failed to render example params as %s: %w", opts.Format, err)
+	}
+	_, err = opts.Out.Write(data)
+	return err
+}
+
+// isLocalBundle reports whether source is a directory containing a massdriver.yaml.
+func isLocalBundle(source string) bool {
+	info, err := os.Stat(filepath.Join(source, "massdriver.yaml"))
+	return err == nil && !info.IsDir()
+}
+
+func localParamsSchema(buildPath string, mdClient *massdriver.Client) (map[string]any, error) {
+	b, err := bundle.Unmarshal(buildPath)
+	if err != nil {
+		return nil, err
+	}
+	// resolved as bundle build does, so the example matches the published schema
+	return b.DereferenceParams(buildPath, schemaResolver(mdClient))
+}
+
+// schemaResolver resolves resource type $refs through mdClient. Without a client, only
+// schemas that don't $ref resource types can be dereferenced.
+func schemaResolver(mdClient *massdriver.Client) bundle.SchemaResolver {
+	if mdClient == nil {
+		return func(context.Context, string) (map[string]any, error) {
+			return nil, errors.New("the Massdriver client is unavailable, so resource type $refs can't be resolved")
+		}
+	}
+	return resourcetype.NewMassdriverResolver(mdClient)
+}
+
+// publishedParamsSchema pulls the bundle into a temporary directory and reads the params
+// schema it was published with.
+func publishedParamsSchema(ctx context.Context, mdClient *massdriver.Client, source string) (map[string]any, error) {
+	if mdClient == nil {
+		return nil, fmt.Errorf("%s is not a local bundle directory and the Massdriver client is unavailable", source)
+	}
+	bundleName, version, found := strings.Cut(source, "@")
+	if !found || version == "" {
+		version = "latest"
+	}
+
+	dir, err := os.MkdirTemp("", "mass-params-example-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	if _, _, err = pullBundle(ctx, mdClient, bundleName, version, dir); err != nil {
+		return nil, fmt.Errorf("error pulling bundle %s: %w", source, err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "schema-params.json"))
+	if errors.Is(err, os.ErrNotExist) {
+		// published without built schemas, so build them from massdriver.yaml
+		return localParamsSchema(dir, mdClient)
+	}
+	if err != nil {
+		return nil, err
+	}
+	sch := map[string]any{}
+	if err = json.Unmarshal(data, &sch); err != nil {
+		return nil, fmt.Errorf("failed to parse the params schema of %s: %w", source, err)
+	}
+	return sch, nil
+}
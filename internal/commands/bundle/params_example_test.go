@@ -0,0 +1,72 @@
+package bundle_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/massdriver-cloud/mass/internal/commands/bundle"
+)
+
+func TestRunParamsExample(t *testing.T) {
+	type test struct {
+		name string
+		opts bundle.ParamsExampleOptions
+		want string
+	}
+	tests := []test{
+		{
+			name: "json",
+			want: `{
+  "backups": true,
+  "database": {
+    "engine": "postgres",
+    "instances": 2,
+    "version": "16"
+  }
+}
+`,
+		},
+		{
+			name: "required only yaml",
+			opts: bundle.ParamsExampleOptions{Format: "yaml", RequiredOnly: true},
+			want: `database:
+  engine: postgres
+  instances: 2
+`,
+		},
+		{
+			name: "tfvars",
+			opts: bundle.ParamsExampleOptions{Format: "tfvars"},
+			want: `backups = true
+database = {
+  engine    = "postgres"
+  instances = 2
+  version   = "16"
+}
+`,
+		},
+	}
+
+	// run from the bundle directory, where bundle build resolves its ./database.json $ref
+	t.Chdir("testdata/params-example")
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			tc.opts.Out = &out
+			if err := bundle.RunParamsExample(context.Background(), nil, ".", tc.opts); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got := out.String(); got != tc.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRunParamsExampleWithoutClient(t *testing.T) {
+	err := bundle.RunParamsExample(context.Background(), nil, "aws-rds@latest", bundle.ParamsExampleOptions{})
+	if err == nil {
+		t.Fatal("expected an error for a published bundle without a client")
+	}
+}
@@ -0,0 +1,51 @@
+package bundle
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/massdriver-cloud/mass/internal/bundle"
+	"github.com/massdriver-cloud/mass/internal/cli"
+)
+
+// RunPlanSteps validates each step's config and prints the order in which the bundle's
+// steps run for action.
+func RunPlanSteps(b *bundle.Bundle, action string) error {
+	plan, err := b.PlanSteps(action)
+	if err != nil {
+		return err
+	}
+
+	configResult := b.LintStepConfig()
+	if configResult.HasErrors() {
+		printLintResult("Step config", configResult)
+		return fmt.Errorf("step config is invalid: %d error(s)", len(configResult.Errors()))
+	}
+	if configResult.HasWarnings() {
+		printLintResult("Step config", configResult)
+	}
+
+	tbl := cli.NewTable("Order", "Step", "Provisioner", "Status", "Config")
+	order := 0
+	for _, step := range plan {
+		position, status := "-", "skipped (skip_on_delete)"
+		if !step.Skipped {
+			order++
+			position, status = fmt.Sprint(order), "run"
+		}
+
+		config := "-"
+		if len(step.Config) > 0 {
+			configBytes, marshalErr := json.Marshal(step.Config)
+			if marshalErr != nil {
+				return fmt.Errorf("failed to marshal config for step %s: %w", step.Path, marshalErr)
+			}
+			config = string(configBytes)
+		}
+
+		tbl.AddRow(position, step.Path, step.Provisioner, status, config)
+	}
+	tbl.Print()
+
+	return nil
+}
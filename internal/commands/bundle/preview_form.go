@@ -0,0 +1,189 @@
+package bundle
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/massdriver-cloud/mass/internal/files"
+	"github.com/massdriver-cloud/mass/internal/jsonschema"
+)
+
+//go:embed templates/preview-form.html
+var previewFormPage []byte
+
+// maxPreviewFormBody caps the size of submitted params.
+const maxPreviewFormBody = 1 << 20
+
+// PreviewFormOptions controls RunPreviewForm.
+type PreviewFormOptions struct {
+	// Address is the host:port to listen on. Defaults to a free localhost port.
+	Address string
+	// OutputPath is where valid submitted params are written, as JSON or YAML
+	// depending on its extension.
+	OutputPath string
+}
+
+// previewFormResult is the response to a validate or submit request.
+type previewFormResult struct {
+	Valid  bool     `json:"valid"`
+	Errors []string `json:"errors,omitempty"`
+	Path   string   `json:"path,omitempty"`
+}
+
+// RunPreviewForm serves a form rendered from the bundle's built params and UI schemas
+// on localhost until ctx is cancelled. Submitted params are validated against the params
+// schema and written to opts.OutputPath.
+func RunPreviewForm(ctx context.Context, buildPath string, opts PreviewFormOptions) error {
+	if opts.Address == "" {
+		opts.Address = "127.0.0.1:0"
+	}
+	if _, _, err := readPreviewSchemas(buildPath); err != nil {
+		return err
+	}
+
+	listenConfig := net.ListenConfig{}
+	listener, err := listenConfig.Listen(ctx, "tcp", opts.Address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", opts.Address, err)
+	}
+
+	server := &http.Server{
+		Handler:           NewPreviewFormHandler(buildPath, opts.OutputPath, listener.Addr().String()),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		//nolint:errcheck // the server is going away either way
+		server.Shutdown(context.Background())
+	}()
+
+	fmt.Printf("Previewing the params form at http://%s\n", listener.Addr())
+	fmt.Printf("Valid params are saved to %s. Press Ctrl-C to stop.\n", opts.OutputPath)
+
+	if serveErr := server.Serve(listener); !errors.Is(serveErr, http.ErrServerClosed) {
+		return serveErr
+	}
+	return nil
+}
+
+// NewPreviewFormHandler returns the HTTP handler behind RunPreviewForm, served on
+// address, the host:port the server is bound to. The schemas are read from buildPath on
+// every request, so rebuilding the bundle only needs a reload.
+func NewPreviewFormHandler(buildPath string, outputPath string, address string) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		//nolint:errcheck // nothing to do if the browser went away
+		w.Write(previewFormPage)
+	})
+
+	mux.HandleFunc("GET /schema", func(w http.ResponseWriter, _ *http.Request) {
+		params, ui, err := readPreviewSchemas(buildPath)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]any{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"params": params, "ui": ui, "output": outputPath})
+	})
+
+	mux.HandleFunc("POST /validate", func(w http.ResponseWriter, r *http.Request) {
+		_, result, status := validatePreviewParams(w, r, buildPath)
+		writeJSON(w, status, result)
+	})
+
+	mux.HandleFunc("POST /submit", func(w http.ResponseWriter, r *http.Request) {
+		params, result, status := validatePreviewParams(w, r, buildPath)
+		if !result.Valid {
+			writeJSON(w, status, result)
+			return
+		}
+		if err := files.Write(outputPath, params); err != nil {
+			writeJSON(w, http.StatusInternalServerError, previewFormResult{Errors: []string{err.Error()}})
+			return
+		}
+		writeJSON(w, http.StatusOK, previewFormResult{Valid: true, Path: outputPath})
+	})
+
+	return sameOrigin(address, mux)
+}
+
+// readPreviewSchemas reads the built params and UI schemas of the bundle.
+func readPreviewSchemas(buildPath string) (map[string]any, map[string]any, error) {
+	schemas := []map[string]any{{}, {}}
+	for i, label := range []string{"params", "ui"} {
+		path := filepath.Join(buildPath, fmt.Sprintf("schema-%s.json", label))
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil, nil, fmt.Errorf("%s not found, run `mass bundle build` first", path)
+			}
+			return nil, nil, err
+		}
+		if err = json.Unmarshal(data, &schemas[i]); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	}
+	return schemas[0], schemas[1], nil
+}
+
+// validatePreviewParams decodes the submitted params and validates them against the
+// built params schema, returning the params, the result and its HTTP status.
+func validatePreviewParams(w http.ResponseWriter, r *http.Request, buildPath string) (map[string]any, previewFormResult, int) {
+	params := map[string]any{}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxPreviewFormBody)).Decode(&params); err != nil {
+		return nil, previewFormResult{Errors: []string{fmt.Sprintf("failed to decode params: %s", err)}}, http.StatusBadRequest
+	}
+
+	sch, err := jsonschema.LoadSchemaFromFile(filepath.Join(buildPath, "schema-params.json"))
+	if err != nil {
+		return nil, previewFormResult{Errors: []string{err.Error()}}, http.StatusInternalServerError
+	}
+	if validateErr := jsonschema.ValidateGo(sch, params); validateErr != nil {
+		return params, previewFormResult{Errors: jsonschema.ValidationMessages(validateErr)}, http.StatusUnprocessableEntity
+	}
+	return params, previewFormResult{Valid: true}, http.StatusOK
+}
+
+// sameOrigin rejects requests sent from other sites, since any page open in the browser
+// can post to a localhost server. The Host must be the address the server is bound to,
+// or localhost on its port, so a page on a domain rebound to 127.0.0.1 is rejected too.
+func sameOrigin(address string, next http.Handler) http.Handler {
+	hosts := []string{address}
+	if host, port, err := net.SplitHostPort(address); err == nil {
+		if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+			hosts = append(hosts, net.JoinHostPort("localhost", port))
+		}
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !slices.Contains(hosts, r.Host) {
+			http.Error(w, fmt.Sprintf("unexpected Host %q, open the form at http://%s", r.Host, address), http.StatusForbidden)
+			return
+		}
+		if origin := r.Header.Get("Origin"); origin != "" {
+			originURL, err := url.Parse(origin)
+			if err != nil || originURL.Host != r.Host {
+				http.Error(w, "cross-origin requests are not allowed", http.StatusForbidden)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	//nolint:errcheck // nothing to do if the browser went away
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,161 @@
+package bundle_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/massdriver-cloud/mass/internal/commands/bundle"
+	"github.com/massdriver-cloud/mass/internal/files"
+)
+
+// previewAddress stands in for the address the preview server is bound to.
+const previewAddress = "127.0.0.1:8080"
+
+func TestPreviewFormHandler(t *testing.T) {
+	type test struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		host       string
+		origin     string
+		wantStatus int
+		wantBody   string
+	}
+	tests := []test{
+		{
+			name:       "page",
+			method:     http.MethodGet,
+			path:       "/",
+			wantStatus: http.StatusOK,
+			wantBody:   "<title>Massdriver params form preview</title>",
+		},
+		{
+			name:       "schema",
+			method:     http.MethodGet,
+			path:       "/schema",
+			wantStatus: http.StatusOK,
+			wantBody:   `"ui":{"ui:order":["image","replicas"]}`,
+		},
+		{
+			name:       "valid",
+			method:     http.MethodPost,
+			path:       "/validate",
+			body:       `{"replicas": 2, "image": {"tag": "latest"}}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"valid":true}`,
+		},
+		{
+			name:       "invalid",
+			method:     http.MethodPost,
+			path:       "/validate",
+			body:       `{"replicas": 0, "image": {}}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantBody:   `at '/replicas': minimum: got 0, want 1`,
+		},
+		{
+			name:       "malformed",
+			method:     http.MethodPost,
+			path:       "/validate",
+			body:       `{"replicas":`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "failed to decode params",
+		},
+		{
+			name:       "cross origin",
+			method:     http.MethodPost,
+			path:       "/submit",
+			body:       `{"replicas": 2, "image": {"tag": "latest"}}`,
+			origin:     "https://attacker.test",
+			wantStatus: http.StatusForbidden,
+			wantBody:   "cross-origin requests are not allowed",
+		},
+		{
+			name:       "localhost",
+			method:     http.MethodGet,
+			path:       "/schema",
+			host:       "localhost:8080",
+			wantStatus: http.StatusOK,
+			wantBody:   `"ui":{"ui:order":["image","replicas"]}`,
+		},
+		{
+			name:       "rebound host",
+			method:     http.MethodPost,
+			path:       "/submit",
+			body:       `{"replicas": 2, "image": {"tag": "latest"}}`,
+			host:       "attacker.test:8080",
+			origin:     "http://attacker.test:8080",
+			wantStatus: http.StatusForbidden,
+			wantBody:   `unexpected Host "attacker.test:8080"`,
+		},
+		{
+			name:       "other port",
+			method:     http.MethodGet,
+			path:       "/schema",
+			host:       "127.0.0.1:9090",
+			wantStatus: http.StatusForbidden,
+			wantBody:   `unexpected Host "127.0.0.1:9090"`,
+		},
+	}
+
+	handler := bundle.NewPreviewFormHandler("testdata/preview-form", filepath.Join(t.TempDir(), "params.json"), previewAddress)
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			req.Host = previewAddress
+			if tc.host != "" {
+				req.Host = tc.host
+			}
+			if tc.origin != "" {
+				req.Header.Set("Origin", tc.origin)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Errorf("got status %d, want %d", rec.Code, tc.wantStatus)
+			}
+			if !strings.Contains(rec.Body.String(), tc.wantBody) {
+				t.Errorf("got body %q, want it to contain %q", rec.Body.String(), tc.wantBody)
+			}
+		})
+	}
+}
+
+func TestPreviewFormSubmit(t *testing.T) {
+	for _, ext := range []string{".json", ".yaml"} {
+		t.Run(ext, func(t *testing.T) {
+			outputPath := filepath.Join(t.TempDir(), "params"+ext)
+			handler := bundle.NewPreviewFormHandler("testdata/preview-form", outputPath, previewAddress)
+
+			req := httptest.NewRequest(http.MethodPost, "/submit", strings.NewReader(`{"replicas": 2, "image": {"tag": "latest"}}`))
+			req.Host = previewAddress
+			req.Header.Set("Origin", "http://"+req.Host)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("got status %d: %s", rec.Code, rec.Body.String())
+			}
+
+			var got map[string]any
+			if err := files.Read(outputPath, &got); err != nil {
+				t.Fatalf("failed to read params file: %v", err)
+			}
+			want := map[string]any{"replicas": float64(2), "image": map[string]any{"tag": "latest"}}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestPreviewFormUnbuilt(t *testing.T) {
+	err := bundle.RunPreviewForm(t.Context(), t.TempDir(), bundle.PreviewFormOptions{OutputPath: "params.json"})
+	if err == nil || !strings.Contains(err.Error(), "run `mass bundle build` first") {
+		t.Errorf("got %v, want an error asking to build the bundle", err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/massdriver-cloud/mass/internal/bundle"
 	"github.com/massdriver-cloud/mass/internal/prettylogs"
 	"github.com/massdriver-cloud/massdriver-sdk-go/massdriver"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
 	"oras.land/oras-go/v2/content/file"
 )
 
@@ -20,19 +21,36 @@ func RunPull(ctx context.Context, mdClient *massdriver.Client, bundleName string
 		prettylogs.Underline(directory),
 	)
 
+	tag, descriptor, pullErr := pullBundle(ctx, mdClient, bundleName, version, directory)
+	if pullErr != nil {
+		return pullErr
+	}
+
+	fmt.Printf("Bundle %s:%s pulled successfully (Digest: %s)\n",
+		prettylogs.Underline(bundleName),
+		prettylogs.Underline(tag),
+		prettylogs.Underline(descriptor.Digest.String()),
+	)
+
+	return nil
+}
+
+// pullBundle pulls a bundle version or release channel into directory, returning the
+// resolved tag and the descriptor of the pulled manifest.
+func pullBundle(ctx context.Context, mdClient *massdriver.Client, bundleName string, version string, directory string) (string, v1.Descriptor, error) {
 	repo, repoErr := mdClient.OciRepos.Target(bundleName)
 	if repoErr != nil {
-		return repoErr
+		return "", v1.Descriptor{}, repoErr
 	}
 
 	tag, tagErr := resolveTag(ctx, mdClient, bundleName, version)
 	if tagErr != nil {
-		return tagErr
+		return "", v1.Descriptor{}, tagErr
 	}
 
 	store, fileErr := file.New(directory)
 	if fileErr != nil {
-		return fmt.Errorf("failed to create file store: %w", fileErr)
+		return "", v1.Descriptor{}, fmt.Errorf("failed to create file store: %w", fileErr)
 	}
 	defer store.Close()
 
@@ -43,16 +61,9 @@ func RunPull(ctx context.Context, mdClient *massdriver.Client, bundleName string
 
 	descriptor, pullErr := puller.PullBundle(ctx, tag)
 	if pullErr != nil {
-		return fmt.Errorf("failed to pull bundle: %w", pullErr)
+		return "", v1.Descriptor{}, fmt.Errorf("failed to pull bundle: %w", pullErr)
 	}
-
-	fmt.Printf("Bundle %s:%s pulled successfully (Digest: %s)\n",
-		prettylogs.Underline(bundleName),
-		prettylogs.Underline(tag),
-		prettylogs.Underline(descriptor.Digest.String()),
-	)
-
-	return nil
+	return tag, descriptor, nil
 }
 
 func resolveTag(ctx context.Context, mdClient *massdriver.Client, bundleName string, version string) (string, error) {
@@ -0,0 +1,409 @@
+package bundle
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/massdriver-cloud/mass/internal/bundle"
+	"github.com/massdriver-cloud/mass/internal/files"
+	"github.com/massdriver-cloud/mass/internal/jsonschema"
+	"github.com/massdriver-cloud/massdriver-sdk-go/massdriver"
+)
+
+// TestOptions controls RunTest.
+type TestOptions struct {
+	// TestsDir holds the test cases. Defaults to the tests directory of the bundle.
+	TestsDir string
+	// Run, when set, only runs the cases whose names match this regular expression.
+	Run string
+	// Update rewrites golden files with the generated output instead of comparing them.
+	Update bool
+	// JUnitPath is a file to write a JUnit XML report to.
+	JUnitPath string
+	// Out receives the go test style output. Defaults to stdout.
+	Out io.Writer
+}
+
+// TestCase is one test of a bundle, read from a YAML or JSON file in the tests directory.
+// Params, Connections and Metadata are either inline values or paths to fixture files,
+// relative to the tests directory.
+type TestCase struct {
+	Description string     `json:"description,omitempty"`
+	Params      any        `json:"params,omitempty"`
+	Connections any        `json:"connections,omitempty"`
+	Metadata    any        `json:"md_metadata,omitempty"`
+	Expect      TestExpect `json:"expect"`
+
+	name string
+}
+
+// TestExpect is what a test case expects of the bundle.
+type TestExpect struct {
+	// Valid is whether params and connections pass validation. Defaults to true, or
+	// false when Errors is set.
+	Valid *bool `json:"valid,omitempty"`
+	// Errors are substrings of the expected validation errors.
+	Errors []string `json:"errors,omitempty"`
+	// Envs are the expected values of app.envs.
+	Envs map[string]string `json:"envs,omitempty"`
+	// Golden maps files generated by the build, relative to the bundle, to golden files
+	// relative to the tests directory.
+	Golden map[string]string `json:"golden,omitempty"`
+}
+
+// testResult is the outcome of one test case.
+type testResult struct {
+	name     string
+	failures []string
+	elapsed  time.Duration
+}
+
+// testHarness holds what every case of one run shares: the bundle built from a copy of
+// the bundle directory and its compiled schemas.
+type testHarness struct {
+	testsDir    string
+	buildDir    string
+	bundle      *bundle.Bundle
+	params      schemaValidator
+	connections schemaValidator
+	update      bool
+}
+
+// schemaValidator validates a document against a compiled schema.
+type schemaValidator func(document any) error
+
+// RunTest builds a copy of the bundle and runs every test case against it: params and
+// connections validate or fail as expected, app.envs evaluate to the expected values and
+// generated files match their golden files. It returns an error if any case fails.
+func RunTest(buildPath string, mdClient *massdriver.Client, opts TestOptions) error {
+	if opts.TestsDir == "" {
+		opts.TestsDir = filepath.Join(buildPath, "tests")
+	}
+	if opts.Out == nil {
+		opts.Out = os.Stdout
+	}
+	var runFilter *regexp.Regexp
+	if opts.Run != "" {
+		var err error
+		if runFilter, err = regexp.Compile(opts.Run); err != nil {
+			return fmt.Errorf("invalid --run pattern: %w", err)
+		}
+	}
+
+	cases, err := readTestCases(opts.TestsDir)
+	if err != nil {
+		return err
+	}
+
+	buildDir, err := os.MkdirTemp("", "mass-bundle-test-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(buildDir)
+
+	start := time.Now()
+	harness, err := newTestHarness(buildPath, buildDir, mdClient, opts)
+	if err != nil {
+		return err
+	}
+
+	results := []testResult{}
+	for _, tc := range cases {
+		if runFilter != nil && !runFilter.MatchString(tc.name) {
+			continue
+		}
+		fmt.Fprintf(opts.Out, "=== RUN   %s\n", tc.name)
+		caseStart := time.Now()
+		result := testResult{name: tc.name, failures: harness.run(tc)}
+		result.elapsed = time.Since(caseStart)
+		results = append(results, result)
+
+		status := "PASS"
+		if len(result.failures) > 0 {
+			status = "FAIL"
+		}
+		for _, failure := range result.failures {
+			fmt.Fprintf(opts.Out, "    %s\n", strings.ReplaceAll(failure, "\n", "\n    "))
+		}
+		fmt.Fprintf(opts.Out, "--- %s: %s (%.2fs)\n", status, tc.name, result.elapsed.Seconds())
+	}
+	elapsed := time.Since(start)
+
+	if opts.JUnitPath != "" {
+		if junitErr := writeJUnit(opts.JUnitPath, harness.bundle.Name, results, elapsed); junitErr != nil {
+			return junitErr
+		}
+	}
+
+	failed := 0
+	for _, result := range results {
+		if len(result.failures) > 0 {
+			failed++
+		}
+	}
+	if failed > 0 {
+		fmt.Fprintln(opts.Out, "FAIL")
+		fmt.Fprintf(opts.Out, "FAIL\t%s\t%.3fs\n", harness.bundle.Name, elapsed.Seconds())
+		return fmt.Errorf("%d of %d test(s) failed", failed, len(results))
+	}
+	fmt.Fprintln(opts.Out, "PASS")
+	fmt.Fprintf(opts.Out, "ok  \t%s\t%.3fs\n", harness.bundle.Name, elapsed.Seconds())
+	return nil
+}
+
+// readTestCases reads every .yaml and .json file directly in testsDir as a test case,
+// named after the file. Subdirectories are left for fixtures and golden files.
+func readTestCases(testsDir string) ([]TestCase, error) {
+	entries, err := os.ReadDir(testsDir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("no tests directory at %s", testsDir)
+		}
+		return nil, err
+	}
+
+	cases := []TestCase{}
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yaml" && ext != ".json") {
+			continue
+		}
+		tc := TestCase{}
+		if readErr := files.Read(filepath.Join(testsDir, entry.Name()), &tc); readErr != nil {
+			return nil, fmt.Errorf("failed to read test case %s: %w", entry.Name(), readErr)
+		}
+		tc.name = strings.TrimSuffix(entry.Name(), ext)
+		cases = append(cases, tc)
+	}
+	if len(cases) == 0 {
+		return nil, fmt.Errorf("no test cases in %s", testsDir)
+	}
+	return cases, nil
+}
+
+// newTestHarness copies the bundle to buildDir and builds it there, so the generated
+// files can be compared without touching the bundle itself.
+func newTestHarness(buildPath string, buildDir string, mdClient *massdriver.Client, opts TestOptions) (*testHarness, error) {
+	if err := copyBundle(buildPath, buildDir, opts.TestsDir); err != nil {
+		return nil, err
+	}
+	b, err := bundle.Unmarshal(buildDir)
+	if err != nil {
+		return nil, err
+	}
+	// $refs resolve against the bundle itself, as in bundle build, since the files they
+	// point to may not be in the copy
+	if err = b.BuildWithOptions(buildDir, schemaResolver(mdClient), bundle.BuildOptions{RefPath: buildPath}); err != nil {
+		return nil, fmt.Errorf("failed to build bundle: %w", err)
+	}
+
+	paramsSchema, err := jsonschema.LoadSchemaFromGo(b.Params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile params schema: %w", err)
+	}
+	connectionsSchema, err := jsonschema.LoadSchemaFromGo(b.Connections)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile connections schema: %w", err)
+	}
+
+	return &testHarness{
+		testsDir: opts.TestsDir,
+		buildDir: buildDir,
+		bundle:   b,
+		params: func(document any) error {
+			return jsonschema.ValidateGo(paramsSchema, document)
+		},
+		connections: func(document any) error {
+			return jsonschema.ValidateGo(connectionsSchema, document)
+		},
+		update: opts.Update,
+	}, nil
+}
+
+// run runs one test case and returns its failures.
+func (h *testHarness) run(tc TestCase) []string {
+	failures := []string{}
+
+	params, err := h.fixture(tc.Params)
+	if err != nil {
+		return append(failures, fmt.Sprintf("params: %s", err))
+	}
+	connections, err := h.fixture(tc.Connections)
+	if err != nil {
+		return append(failures, fmt.Sprintf("connections: %s", err))
+	}
+
+	failures = append(failures, h.checkValidation(tc, params, connections)...)
+
+	if len(tc.Expect.Envs) > 0 {
+		metadata := placeholderMetadata()
+		if tc.Metadata != nil {
+			if metadata, err = h.fixture(tc.Metadata); err != nil {
+				return append(failures, fmt.Sprintf("md_metadata: %s", err))
+			}
+		}
+		failures = append(failures, h.checkEnvs(tc, params, connections, metadata)...)
+	}
+
+	for _, generated := range slices.Sorted(maps.Keys(tc.Expect.Golden)) {
+		if failure := h.checkGolden(generated, tc.Expect.Golden[generated]); failure != "" {
+			failures = append(failures, failure)
+		}
+	}
+	return failures
+}
+
+// fixture returns an inline fixture value, or reads it from the file it names.
+func (h *testHarness) fixture(value any) (map[string]any, error) {
+	switch fixture := value.(type) {
+	case nil:
+		return map[string]any{}, nil
+	case map[string]any:
+		return fixture, nil
+	case string:
+		read := map[string]any{}
+		if err := files.Read(filepath.Join(h.testsDir, fixture), &read); err != nil {
+			return nil, err
+		}
+		return read, nil
+	}
+	return nil, fmt.Errorf("expected an object or a fixture file path, got %T", value)
+}
+
+func (h *testHarness) checkValidation(tc TestCase, params, connections map[string]any) []string {
+	messages := []string{}
+	if tc.Params != nil {
+		if err := h.params(params); err != nil {
+			for _, message := range jsonschema.ValidationMessages(err) {
+				messages = append(messages, "params "+message)
+			}
+		}
+	}
+	if tc.Connections != nil {
+		if err := h.connections(connections); err != nil {
+			for _, message := range jsonschema.ValidationMessages(err) {
+				messages = append(messages, "connections "+message)
+			}
+		}
+	}
+
+	wantValid := len(tc.Expect.Errors) == 0
+	if tc.Expect.Valid != nil {
+		wantValid = *tc.Expect.Valid
+	}
+
+	switch {
+	case wantValid && len(messages) > 0:
+		return []string{"expected validation to pass, but it failed:\n  " + strings.Join(messages, "\n  ")}
+	case !wantValid && len(messages) == 0:
+		return []string{"expected validation to fail, but it passed"}
+	}
+
+	failures := []string{}
+	for _, want := range tc.Expect.Errors {
+		if !slices.ContainsFunc(messages, func(message string) bool { return strings.Contains(message, want) }) {
+			failures = append(failures, fmt.Sprintf("expected a validation error containing %q, got:\n  %s", want, strings.Join(messages, "\n  ")))
+		}
+	}
+	return failures
+}
+
+func (h *testHarness) checkEnvs(tc TestCase, params, connections map[string]any, metadata any) []string {
+	var queries map[string]string
+	if h.bundle.AppSpec != nil {
+		queries = h.bundle.AppSpec.Envs
+	}
+	input := map[string]any{"params": params, "connections": connections, "md_metadata": metadata}
+	results := bundle.ParseEnvironmentVariables(input, queries)
+
+	failures := []string{}
+	for _, name := range slices.Sorted(maps.Keys(tc.Expect.Envs)) {
+		want := tc.Expect.Envs[name]
+		got, declared := results[name]
+		switch {
+		case !declared:
+			failures = append(failures, fmt.Sprintf("env %s: not declared in app.envs", name))
+		case got.Error != "":
+			failures = append(failures, fmt.Sprintf("env %s: %s", name, got.Error))
+		case got.Value != want:
+			failures = append(failures, fmt.Sprintf("env %s: got %q, want %q", name, got.Value, want))
+		}
+	}
+	return failures
+}
+
+// checkGolden compares a generated file with its golden file, or rewrites the golden file
+// when updating.
+func (h *testHarness) checkGolden(generated string, golden string) string {
+	got, err := os.ReadFile(filepath.Join(h.buildDir, generated))
+	if err != nil {
+		return fmt.Sprintf("%s: the build didn't generate it: %s", generated, err)
+	}
+
+	goldenPath := filepath.Join(h.testsDir, golden)
+	if h.update {
+		if err = os.MkdirAll(filepath.Dir(goldenPath), 0755); err == nil {
+			err = os.WriteFile(goldenPath, got, 0644)
+		}
+		if err != nil {
+			return fmt.Sprintf("%s: failed to update golden file %s: %s", generated, golden, err)
+		}
+		return ""
+	}
+
+	want, err := os.ReadFile(goldenPath)
+	if err != nil {
+		return fmt.Sprintf("%s: failed to read golden file %s, run with --update to create it: %s", generated, golden, err)
+	}
+	if !bytes.Equal(got, want) {
+		diff, diffErr := unifiedDiff(golden, want, got)
+		if diffErr != nil {
+			return fmt.Sprintf("%s doesn't match golden file %s", generated, golden)
+		}
+		return fmt.Sprintf("%s doesn't match golden file %s, run with --update to accept it:\n%s", generated, golden, strings.TrimRight(diff, "\n"))
+	}
+	return ""
+}
+
+// copyBundle copies the bundle's files to dst, skipping hidden directories such as
+// .terraform, the tests directory and anything that isn't a regular file.
+func copyBundle(src string, dst string, testsDir string) error {
+	absTestsDir, err := filepath.Abs(testsDir)
+	if err != nil {
+		return err
+	}
+	return filepath.WalkDir(src, func(path string, entry fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		rel, relErr := filepath.Rel(src, path)
+		if relErr != nil {
+			return relErr
+		}
+		if entry.IsDir() {
+			absPath, _ := filepath.Abs(path)
+			if path != src && (strings.HasPrefix(entry.Name(), ".") || absPath == absTestsDir) {
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(filepath.Join(dst, rel), 0755)
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		data, readErr := os.ReadFile(path)
+		if readErr != nil {
+			return readErr
+		}
+		return os.WriteFile(filepath.Join(dst, rel), data, 0644)
+	})
+}
@@ -0,0 +1,99 @@
+package bundle_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/massdriver-cloud/mass/internal/commands/bundle"
+)
+
+func TestRunTest(t *testing.T) {
+	// run from the bundle directory, where bundle build resolves its ./schemas $ref
+	t.Chdir("testdata/bundle-test")
+	var out bytes.Buffer
+	junitPath := filepath.Join(t.TempDir(), "report.xml")
+	err := bundle.RunTest(".", nil, bundle.TestOptions{JUnitPath: junitPath, Out: &out})
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, out.String())
+	}
+
+	for _, want := range []string{"--- PASS: too-many-replicas", "--- PASS: valid", "--- PASS: variables", "\nPASS\n"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output is missing %q:\n%s", want, out.String())
+		}
+	}
+
+	report, err := os.ReadFile(junitPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(report), `tests="3" failures="0"`) {
+		t.Errorf("unexpected JUnit report:\n%s", report)
+	}
+}
+
+func TestRunTestFailures(t *testing.T) {
+	t.Chdir("testdata/bundle-test")
+	testsDir := t.TempDir()
+	cases := map[string]string{
+		"wrong-env.yaml": `
+params:
+  replicas: 2
+connections:
+  postgres:
+    data:
+      host: db.example.com
+expect:
+  envs:
+    REPLICAS: "3"
+`,
+		"unexpectedly-valid.yaml": `
+params:
+  replicas: 2
+expect:
+  errors:
+    - at '/replicas'
+`,
+	}
+	for name, content := range cases {
+		if err := os.WriteFile(filepath.Join(testsDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var out bytes.Buffer
+	junitPath := filepath.Join(t.TempDir(), "report.xml")
+	err := bundle.RunTest(".", nil, bundle.TestOptions{TestsDir: testsDir, JUnitPath: junitPath, Out: &out})
+	if err == nil || err.Error() != "2 of 2 test(s) failed" {
+		t.Fatalf("got error %v, want 2 of 2 test(s) failed\n%s", err, out.String())
+	}
+
+	for _, want := range []string{"--- FAIL: unexpectedly-valid", "--- FAIL: wrong-env", `REPLICAS`, "\nFAIL\n"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output is missing %q:\n%s", want, out.String())
+		}
+	}
+
+	report, err := os.ReadFile(junitPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(report), `tests="2" failures="2"`) {
+		t.Errorf("unexpected JUnit report:\n%s", report)
+	}
+}
+
+func TestRunTestFilter(t *testing.T) {
+	t.Chdir("testdata/bundle-test")
+	var out bytes.Buffer
+	err := bundle.RunTest(".", nil, bundle.TestOptions{Run: "^valid$", Out: &out})
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, out.String())
+	}
+	if strings.Contains(out.String(), "too-many-replicas") || !strings.Contains(out.String(), "--- PASS: valid") {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+}
@@ -0,0 +1,36 @@
+// This file is auto-generated by massdriver from your massdriver.yaml file.
+// Any changes made directly to this file will be overwritten on the next build.
+// To opt a variable out of regeneration, move it to another file (e.g. variables.tf).
+variable "log_level" {
+  type    = string
+  default = null
+}
+variable "md_metadata" {
+  type = object({
+    default_tags = map(string)
+    deployment = object({
+      id = string
+    })
+    name_prefix = string
+    observability = object({
+      alarm_webhook_url = string
+    })
+    package = object({
+      created_at             = string
+      deployment_enqueued_at = string
+      previous_status        = string
+      updated_at             = string
+    })
+    target = object({
+      contact_email = string
+    })
+  })
+}
+variable "postgres" {
+  type = object({
+    data = object({})
+  })
+}
+variable "replicas" {
+  type = number
+}
@@ -0,0 +1,146 @@
+package bundle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/massdriver-cloud/mass/internal/bundle"
+	"github.com/massdriver-cloud/mass/internal/filewatch"
+	"github.com/massdriver-cloud/mass/internal/prettylogs"
+	"github.com/massdriver-cloud/mass/internal/resourcetype"
+
+	"github.com/massdriver-cloud/massdriver-sdk-go/massdriver"
+)
+
+const (
+	defaultWatchInterval = 500 * time.Millisecond
+	defaultWatchDebounce = 300 * time.Millisecond
+)
+
+// WatchOptions controls RunBuildWatch.
+type WatchOptions struct {
+	Build bundle.BuildOptions
+	// Interval is how often watched files are polled. Defaults to 500ms.
+	Interval time.Duration
+	// Debounce is how long files must stay unchanged before rebuilding. Defaults to 300ms.
+	Debounce time.Duration
+	// Out receives the build and lint summaries. Defaults to stdout.
+	Out io.Writer
+}
+
+// RunBuildWatch builds and lints the bundle, then does so again whenever massdriver.yaml,
+// a local file it $refs, or a step directory changes, until ctx is cancelled. The set of
+// watched files is refreshed after every build, so added or removed $refs are tracked.
+func RunBuildWatch(ctx context.Context, buildPath string, mdClient *massdriver.Client, opts WatchOptions) error {
+	if opts.Interval == 0 {
+		opts.Interval = defaultWatchInterval
+	}
+	if opts.Debounce == 0 {
+		opts.Debounce = defaultWatchDebounce
+	}
+	if opts.Out == nil {
+		opts.Out = os.Stdout
+	}
+
+	watched := []string{filepath.Join(buildPath, "massdriver.yaml")}
+	for {
+		watched = buildAndLint(buildPath, mdClient, opts, watched)
+		baseline := filewatch.Take(watched)
+
+		changed, err := filewatch.Wait(ctx, watched, baseline, filewatch.Options{Interval: opts.Interval, Debounce: opts.Debounce})
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return nil
+			}
+			return err
+		}
+		fmt.Fprintf(opts.Out, "%s changed, rebuilding...\n", describeChanged(buildPath, changed))
+	}
+}
+
+// buildAndLint runs a single build and lint, printing a one-line summary followed by any
+// issues, and returns the paths to watch for the next build. If the bundle can't be read,
+// the previous paths are kept.
+func buildAndLint(buildPath string, mdClient *massdriver.Client, opts WatchOptions, previous []string) []string {
+	stamp := time.Now().Format("15:04:05")
+	start := time.Now()
+
+	b, err := bundle.Unmarshal(buildPath)
+	if err != nil {
+		fmt.Fprintf(opts.Out, "[%s] %s Build failed: %s\n", stamp, prettylogs.Red("✗"), err)
+		return previous
+	}
+
+	err = b.BuildWithOptions(buildPath, resourcetype.NewMassdriverResolver(mdClient), opts.Build)
+	watched := watchPaths(buildPath, b)
+	if err != nil {
+		fmt.Fprintf(opts.Out, "[%s] %s Build failed: %s\n", stamp, prettylogs.Red("✗"), err)
+		return watched
+	}
+	elapsed := time.Since(start).Round(time.Millisecond)
+
+	var lint bundle.LintResult
+	for _, check := range lintChecks(b, mdClient) {
+		lint.Merge(check.result)
+	}
+	errorCount, warningCount := len(lint.Errors()), len(lint.Warnings())
+	switch {
+	case errorCount > 0:
+		fmt.Fprintf(opts.Out, "[%s] %s Built in %s, lint failed with %d error(s) and %d warning(s)\n", stamp, prettylogs.Red("✗"), elapsed, errorCount, warningCount)
+	case warningCount > 0:
+		fmt.Fprintf(opts.Out, "[%s] %s Built in %s, lint passed with %d warning(s)\n", stamp, prettylogs.Orange("!"), elapsed, warningCount)
+	default:
+		fmt.Fprintf(opts.Out, "[%s] %s Built in %s, lint passed\n", stamp, prettylogs.Green("✓"), elapsed)
+	}
+	for _, issue := range lint.Issues {
+		fmt.Fprintf(opts.Out, "    %s %s: %s\n", issue.Severity, issue.Rule, issue.Message)
+	}
+
+	return watched
+}
+
+// watchPaths returns massdriver.yaml, the local files reached through its $refs and the
+// directory of every step.
+func watchPaths(buildPath string, b *bundle.Bundle) []string {
+	paths := []string{filepath.Join(buildPath, "massdriver.yaml")}
+	paths = append(paths, b.RefFiles()...)
+	for _, step := range b.Steps {
+		stepPath := filepath.Join(buildPath, step.Path)
+		if !slices.Contains(paths, stepPath) {
+			paths = append(paths, stepPath)
+		}
+	}
+	return paths
+}
+
+// describeChanged summarizes changed paths relative to the bundle, e.g. "massdriver.yaml"
+// or "src/main.tf and 2 other files".
+func describeChanged(buildPath string, changed []string) string {
+	names := make([]string, 0, len(changed))
+	absBuildPath, _ := filepath.Abs(buildPath)
+	for _, path := range changed {
+		absPath, _ := filepath.Abs(path)
+		if rel, err := filepath.Rel(absBuildPath, absPath); err == nil && !strings.HasPrefix(rel, "..") {
+			path = rel
+		}
+		names = append(names, path)
+	}
+
+	switch len(names) {
+	case 0:
+		return "Nothing"
+	case 1:
+		return names[0]
+	case 2:
+		return names[0] + " and 1 other file"
+	default:
+		return fmt.Sprintf("%s and %d other files", names[0], len(names)-1)
+	}
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/manifoldco/promptui"
+	"github.com/massdriver-cloud/mass/internal/api"
 	"github.com/massdriver-cloud/mass/internal/jsonschema"
 	"github.com/massdriver-cloud/mass/internal/resourcetype"
 	"github.com/massdriver-cloud/massdriver-sdk-go/massdriver"
@@ -29,6 +30,8 @@ type API interface {
 	DeleteResource(ctx context.Context, id string) (*types.Resource, error)
 	GetResourceType(ctx context.Context, name string) (*resourcetype.ResourceType, error)
 	ListResourceTypes(ctx context.Context) ([]resourcetype.ResourceType, error)
+	ListResources(ctx context.Context, in resources.ListInput) ([]types.Resource, error)
+	ListEnvironmentResourceRefs(ctx context.Context) ([]api.EnvironmentResourceRefs, error)
 }
 
 // NewAPI returns the production [API] backed by the SDK client.
@@ -60,6 +63,14 @@ func (s sdkAPI) ListResourceTypes(ctx context.Context) ([]resourcetype.ResourceT
 	return resourcetype.List(ctx, s.c)
 }
 
+func (s sdkAPI) ListResources(ctx context.Context, in resources.ListInput) ([]types.Resource, error) {
+	return types.Collect(s.c.Resources.Iter(ctx, in))
+}
+
+func (s sdkAPI) ListEnvironmentResourceRefs(ctx context.Context) ([]api.EnvironmentResourceRefs, error) {
+	return api.ListEnvironmentResourceRefs(ctx, s.c)
+}
+
 // RunCreate reads an resource from a file, validates it, and creates it in Massdriver.
 func RunCreate(ctx context.Context, api API, resourceName string, resourceType string, resourceFile string) (string, error) {
 	bytes, readErr := os.ReadFile(resourceFile)
@@ -73,7 +84,7 @@ func RunCreate(ctx context.Context, api API, resourceName string, resourceType s
 		return "", unmarshalErr
 	}
 
-	if validateErr := validateResource(ctx, api, resourceType, payload); validateErr != nil {
+	if _, validateErr := validateResource(ctx, api, resourceType, payload); validateErr != nil {
 		return "", validateErr
 	}
 
@@ -92,17 +103,19 @@ func RunCreate(ctx context.Context, api API, resourceName string, resourceType s
 	return resp.ID, nil
 }
 
-func validateResource(ctx context.Context, api API, resourceTypeName string, resource map[string]any) error {
+// validateResource validates resource against the schema of its resource type,
+// returning the resource type.
+func validateResource(ctx context.Context, api API, resourceTypeName string, resource map[string]any) (*resourcetype.ResourceType, error) {
 	rt, typeErr := api.GetResourceType(ctx, resourceTypeName)
 	if typeErr != nil {
-		return typeErr
+		return nil, typeErr
 	}
 
 	sch, schemaErr := jsonschema.LoadSchemaFromGo(rt.Schema)
 	if schemaErr != nil {
-		return fmt.Errorf("failed to compile resource definition schema: %w", schemaErr)
+		return nil, fmt.Errorf("failed to compile resource definition schema: %w", schemaErr)
 	}
-	return jsonschema.ValidateGo(sch, resource)
+	return rt, jsonschema.ValidateGo(sch, resource)
 }
 
 // CreatePrompt holds the user-supplied data needed to create a resource.
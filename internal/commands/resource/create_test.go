@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/massdriver-cloud/mass/internal/api"
 	"github.com/massdriver-cloud/mass/internal/commands/resource"
 	"github.com/massdriver-cloud/mass/internal/resourcetype"
 
@@ -17,10 +18,14 @@ type fakeResourceAPI struct {
 	resource      *types.Resource
 	resourceType  *resourcetype.ResourceType
 	resourceTypes []resourcetype.ResourceType
+	resources     []types.Resource
+	environments  []api.EnvironmentResourceRefs
 
 	getResourceErr      error
 	getResourceTypeErr  error
 	listResourceTypeErr error
+	listResourcesErr    error
+	listEnvironmentsErr error
 	createErr           error
 	updateErr           error
 	deleteErr           error
@@ -30,6 +35,8 @@ type fakeResourceAPI struct {
 	gotUpdateInput  resources.UpdateInput
 	gotUpdateID     string
 	gotDeleteID     string
+	gotCreateNames  []string
+	gotUpdateIDs    []string
 }
 
 func (f *fakeResourceAPI) GetResource(_ context.Context, _ string) (*types.Resource, error) {
@@ -39,6 +46,7 @@ func (f *fakeResourceAPI) GetResource(_ context.Context, _ string) (*types.Resou
 func (f *fakeResourceAPI) CreateResource(_ context.Context, resourceTypeID string, in resources.CreateInput) (*types.Resource, error) {
 	f.gotCreateTypeID = resourceTypeID
 	f.gotCreateInput = in
+	f.gotCreateNames = append(f.gotCreateNames, in.Name)
 	if f.createErr != nil {
 		return nil, f.createErr
 	}
@@ -48,6 +56,7 @@ func (f *fakeResourceAPI) CreateResource(_ context.Context, resourceTypeID strin
 func (f *fakeResourceAPI) UpdateResource(_ context.Context, id string, in resources.UpdateInput) (*types.Resource, error) {
 	f.gotUpdateID = id
 	f.gotUpdateInput = in
+	f.gotUpdateIDs = append(f.gotUpdateIDs, id)
 	if f.updateErr != nil {
 		return nil, f.updateErr
 	}
@@ -70,6 +79,14 @@ func (f *fakeResourceAPI) ListResourceTypes(_ context.Context) ([]resourcetype.R
 	return f.resourceTypes, f.listResourceTypeErr
 }
 
+func (f *fakeResourceAPI) ListResources(_ context.Context, _ resources.ListInput) ([]types.Resource, error) {
+	return f.resources, f.listResourcesErr
+}
+
+func (f *fakeResourceAPI) ListEnvironmentResourceRefs(_ context.Context) ([]api.EnvironmentResourceRefs, error) {
+	return f.environments, f.listEnvironmentsErr
+}
+
 func TestResourceImport(t *testing.T) {
 	api := &fakeResourceAPI{
 		resource: &types.Resource{ID: "resource-id", Name: "resource-name"},
@@ -5,13 +5,18 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
 	"strings"
+
+	"github.com/massdriver-cloud/mass/internal/prettylogs"
 )
 
 // RunDelete removes a resource by ID, prompting for confirmation unless force
 // is set. The prompt requires the caller to retype the resource's name to
 // proceed — matches the safety pattern used for `mass project delete` and
-// `mass resource-type delete`.
+// `mass resource-type delete`. A resource that environments or instances still
+// depend on isn't deleted unless force is set. If usages can't be looked up, a
+// warning is printed and the confirmation prompt still guards the delete.
 func RunDelete(ctx context.Context, api API, resourceID string, force bool, in io.Reader) error {
 	res, err := api.GetResource(ctx, resourceID)
 	if err != nil {
@@ -19,6 +24,16 @@ func RunDelete(ctx context.Context, api API, resourceID string, force bool, in i
 	}
 
 	if !force {
+		usages, usagesErr := FindUsages(ctx, api, resourceID)
+		if usagesErr != nil {
+			fmt.Println(prettylogs.Orange(fmt.Sprintf("Warning: unable to check whether resource `%s` is in use: %s", res.Name, usagesErr)))
+		}
+		if len(usages) > 0 {
+			fmt.Printf("Resource `%s` is used by:\n", res.Name)
+			PrintUsages(os.Stdout, usages)
+			return fmt.Errorf("resource %s has %d usage(s), remove them or use --force to delete it anyway", res.Name, len(usages))
+		}
+
 		fmt.Printf("WARNING: This will permanently delete resource `%s`.\n", res.Name)
 		fmt.Printf("Type `%s` to confirm deletion: ", res.Name)
 		reader := bufio.NewReader(in)
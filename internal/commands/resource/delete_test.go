@@ -1,6 +1,7 @@
 package resource_test
 
 import (
+	"errors"
 	"strings"
 	"testing"
 
@@ -50,3 +51,48 @@ func TestResourceDeleteCancelled(t *testing.T) {
 		t.Errorf("expected no delete call, but got id %q", api.gotDeleteID)
 	}
 }
+
+func TestResourceDeleteInUse(t *testing.T) {
+	// A resource environments or instances use isn't deleted without --force,
+	// even when the name is retyped.
+	fake := &fakeResourceAPI{
+		resource:     &types.Resource{ID: "vpc-id", Name: "doomed"},
+		environments: usageEnvironments,
+	}
+	err := resource.RunDelete(t.Context(), fake, "vpc-id", false, strings.NewReader("doomed\n"))
+	if err == nil || err.Error() != "resource doomed has 3 usage(s), remove them or use --force to delete it anyway" {
+		t.Fatalf("got error %v, want in-use error", err)
+	}
+	if fake.gotDeleteID != "" {
+		t.Errorf("expected no delete call, got %q", fake.gotDeleteID)
+	}
+
+	if err = resource.RunDelete(t.Context(), fake, "vpc-id", true, strings.NewReader("")); err != nil {
+		t.Fatal(err)
+	}
+	if fake.gotDeleteID != "vpc-id" {
+		t.Errorf("expected --force to delete, got %q", fake.gotDeleteID)
+	}
+}
+
+func TestResourceDeleteUsageLookupFails(t *testing.T) {
+	// A failed usage lookup warns but still lets the retyped name confirm the delete.
+	fake := &fakeResourceAPI{
+		resource:            &types.Resource{ID: "rid-1", Name: "doomed"},
+		listEnvironmentsErr: errors.New("server error"),
+	}
+
+	if err := resource.RunDelete(t.Context(), fake, "rid-1", false, strings.NewReader("nope\n")); err != nil {
+		t.Fatal(err)
+	}
+	if fake.gotDeleteID != "" {
+		t.Errorf("expected no delete call without confirmation, got %q", fake.gotDeleteID)
+	}
+
+	if err := resource.RunDelete(t.Context(), fake, "rid-1", false, strings.NewReader("doomed\n")); err != nil {
+		t.Fatal(err)
+	}
+	if fake.gotDeleteID != "rid-1" {
+		t.Errorf("got delete id %q, wanted rid-1", fake.gotDeleteID)
+	}
+}
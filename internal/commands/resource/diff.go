@@ -0,0 +1,213 @@
+package resource
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/massdriver-cloud/mass/internal/prettylogs"
+)
+
+// SensitiveKeyword marks a schema property whose values are masked in diffs.
+const SensitiveKeyword = "$md.sensitive"
+
+const maskedValue = "(sensitive)"
+
+// FieldOp is the kind of change made to a field.
+type FieldOp string
+
+const (
+	// FieldAdded is a field that's only in the new payload.
+	FieldAdded FieldOp = "added"
+	// FieldRemoved is a field that's only in the existing payload.
+	FieldRemoved FieldOp = "removed"
+	// FieldChanged is a field whose value changed.
+	FieldChanged FieldOp = "changed"
+)
+
+// FieldChange is a change to one field of a resource payload. Before and After are
+// the JSON of the values, or masked for fields marked sensitive in the schema.
+type FieldChange struct {
+	Path   string
+	Op     FieldOp
+	Before string
+	After  string
+}
+
+// DiffPayloads compares two resource payloads field by field, returning the changes
+// sorted by path. Objects are compared key by key; any other value, including an
+// array, is compared as a whole. Values of properties marked with $md.sensitive in
+// schema, nested under one, or holding one, like an array whose items have a
+// sensitive field, are masked. Properties declared through allOf, anyOf and oneOf count.
+func DiffPayloads(schema map[string]any, before, after map[string]any) []FieldChange {
+	beforeFields := map[string]any{}
+	afterFields := map[string]any{}
+	flattenPayload("", before, beforeFields)
+	flattenPayload("", after, afterFields)
+
+	allFields := maps.Clone(beforeFields)
+	maps.Copy(allFields, afterFields)
+
+	changes := []FieldChange{}
+	for _, path := range slices.Sorted(maps.Keys(allFields)) {
+		beforeValue, inBefore := beforeFields[path]
+		afterValue, inAfter := afterFields[path]
+		change := FieldChange{Path: path}
+		switch {
+		case !inBefore:
+			change.Op = FieldAdded
+		case !inAfter:
+			change.Op = FieldRemoved
+		case !reflect.DeepEqual(normalizeJSON(beforeValue), normalizeJSON(afterValue)):
+			change.Op = FieldChanged
+		default:
+			continue
+		}
+
+		sensitive := isSensitive(schema, path)
+		if inBefore {
+			change.Before = renderFieldValue(beforeValue, sensitive)
+		}
+		if inAfter {
+			change.After = renderFieldValue(afterValue, sensitive)
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// PrintFieldChanges writes changes to out, one indented line per field.
+func PrintFieldChanges(out io.Writer, indent string, changes []FieldChange) {
+	for _, change := range changes {
+		switch change.Op {
+		case FieldAdded:
+			fmt.Fprintln(out, prettylogs.Green(fmt.Sprintf("%s+ %s: %s", indent, change.Path, change.After)))
+		case FieldRemoved:
+			fmt.Fprintln(out, prettylogs.Red(fmt.Sprintf("%s- %s: %s", indent, change.Path, change.Before)))
+		case FieldChanged:
+			fmt.Fprintln(out, prettylogs.Orange(fmt.Sprintf("%s~ %s: %s => %s", indent, change.Path, change.Before, change.After)))
+		}
+	}
+}
+
+// flattenPayload collects the leaf values of value into fields, keyed by JSON pointer.
+// Empty objects are leaves so adding or removing one shows up.
+func flattenPayload(path string, value any, fields map[string]any) {
+	obj, isObject := value.(map[string]any)
+	if !isObject || len(obj) == 0 {
+		if path != "" {
+			fields[path] = value
+		}
+		return
+	}
+	for key, child := range obj {
+		flattenPayload(path+"/"+escapePointer(key), child, fields)
+	}
+}
+
+// isSensitive reports whether the schema property at path, or one it's nested
+// under, is marked sensitive. Since arrays and empty objects are compared whole, the
+// value at path is also sensitive when anything inside it, such as a field of its
+// array items, is marked sensitive.
+func isSensitive(schema map[string]any, path string) bool {
+	nodes := composedSchemas(schema)
+	for _, segment := range strings.Split(strings.TrimPrefix(path, "/"), "/") {
+		key := unescapePointer(segment)
+		children := []map[string]any{}
+		for _, node := range nodes {
+			props, _ := node["properties"].(map[string]any)
+			if child, ok := props[key].(map[string]any); ok {
+				children = append(children, composedSchemas(child)...)
+			} else if child, ok := node["additionalProperties"].(map[string]any); ok {
+				// undeclared keys of a map-like object fall under additionalProperties
+				children = append(children, composedSchemas(child)...)
+			}
+		}
+		if len(children) == 0 {
+			return false
+		}
+		for _, child := range children {
+			if sensitive, _ := child[SensitiveKeyword].(bool); sensitive {
+				return true
+			}
+		}
+		nodes = children
+	}
+	return slices.ContainsFunc(nodes, containsSensitive)
+}
+
+// composedSchemas returns schema and every schema it's composed of through allOf,
+// anyOf and oneOf.
+func composedSchemas(schema map[string]any) []map[string]any {
+	schemas := []map[string]any{schema}
+	for _, keyword := range []string{"allOf", "anyOf", "oneOf"} {
+		subschemas, _ := schema[keyword].([]any)
+		for _, sub := range subschemas {
+			if subSchema, ok := sub.(map[string]any); ok {
+				schemas = append(schemas, composedSchemas(subSchema)...)
+			}
+		}
+	}
+	return schemas
+}
+
+// containsSensitive reports whether schema or any schema nested in it is marked sensitive.
+func containsSensitive(schema map[string]any) bool {
+	if sensitive, _ := schema[SensitiveKeyword].(bool); sensitive {
+		return true
+	}
+	nested := []any{schema["additionalProperties"], schema["additionalItems"], schema["items"], schema["then"], schema["else"]}
+	for _, keyword := range []string{"properties", "patternProperties"} {
+		if props, ok := schema[keyword].(map[string]any); ok {
+			nested = append(nested, slices.Collect(maps.Values(props))...)
+		}
+	}
+	for _, keyword := range []string{"items", "prefixItems", "allOf", "anyOf", "oneOf"} {
+		if subschemas, ok := schema[keyword].([]any); ok {
+			nested = append(nested, subschemas...)
+		}
+	}
+	for _, sub := range nested {
+		if subSchema, ok := sub.(map[string]any); ok && containsSensitive(subSchema) {
+			return true
+		}
+	}
+	return false
+}
+
+func renderFieldValue(value any, sensitive bool) string {
+	if sensitive {
+		return maskedValue
+	}
+	rendered, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(rendered)
+}
+
+// normalizeJSON round-trips value through JSON so numbers decoded from different
+// sources compare equal.
+func normalizeJSON(value any) any {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var normalized any
+	if err = json.Unmarshal(data, &normalized); err != nil {
+		return value
+	}
+	return normalized
+}
+
+func escapePointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
+
+func unescapePointer(segment string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(segment, "~1", "/"), "~0", "~")
+}
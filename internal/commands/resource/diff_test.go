@@ -0,0 +1,110 @@
+package resource_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/massdriver-cloud/mass/internal/commands/resource"
+)
+
+func TestDiffPayloads(t *testing.T) {
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"authentication": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"hostname": map[string]any{"type": "string"},
+					"password": map[string]any{"type": "string", "$md.sensitive": true},
+				},
+			},
+			"credentials": map[string]any{
+				"type":          "object",
+				"$md.sensitive": true,
+			},
+			"tags": map[string]any{
+				"type":                 "object",
+				"additionalProperties": map[string]any{"type": "string"},
+			},
+			"users": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"name":     map[string]any{"type": "string"},
+						"password": map[string]any{"type": "string", "$md.sensitive": true},
+					},
+				},
+			},
+		},
+		"allOf": []any{
+			map[string]any{
+				"properties": map[string]any{
+					"token": map[string]any{"type": "string", "$md.sensitive": true},
+				},
+			},
+		},
+	}
+
+	type test struct {
+		name   string
+		before map[string]any
+		after  map[string]any
+		want   []resource.FieldChange
+	}
+	tests := []test{
+		{
+			name:   "no changes",
+			before: map[string]any{"authentication": map[string]any{"hostname": "db", "port": 5432}},
+			after:  map[string]any{"authentication": map[string]any{"hostname": "db", "port": float64(5432)}},
+			want:   []resource.FieldChange{},
+		},
+		{
+			name: "added, removed and changed fields",
+			before: map[string]any{
+				"authentication": map[string]any{"hostname": "db-1", "password": "hunter2"},
+				"tags":           map[string]any{"team": "data"},
+			},
+			after: map[string]any{
+				"authentication": map[string]any{"hostname": "db-2", "password": "hunter3"},
+				"credentials":    map[string]any{"key": "secret"},
+				"tags":           map[string]any{"team": "data", "env/name": "prod"},
+				"zones":          []any{"a", "b"},
+			},
+			want: []resource.FieldChange{
+				{Path: "/authentication/hostname", Op: resource.FieldChanged, Before: `"db-1"`, After: `"db-2"`},
+				{Path: "/authentication/password", Op: resource.FieldChanged, Before: "(sensitive)", After: "(sensitive)"},
+				{Path: "/credentials/key", Op: resource.FieldAdded, After: "(sensitive)"},
+				{Path: "/tags/env~1name", Op: resource.FieldAdded, After: `"prod"`},
+				{Path: "/zones", Op: resource.FieldAdded, After: `["a","b"]`},
+			},
+		},
+		{
+			name:   "sensitive fields in array items and composed schemas",
+			before: map[string]any{"users": []any{map[string]any{"name": "admin", "password": "hunter2"}}, "token": "abc"},
+			after:  map[string]any{"users": []any{map[string]any{"name": "admin", "password": "hunter3"}}, "token": "def"},
+			want: []resource.FieldChange{
+				{Path: "/token", Op: resource.FieldChanged, Before: "(sensitive)", After: "(sensitive)"},
+				{Path: "/users", Op: resource.FieldChanged, Before: "(sensitive)", After: "(sensitive)"},
+			},
+		},
+		{
+			name:   "removed objects",
+			before: map[string]any{"tags": map[string]any{}, "authentication": map[string]any{"hostname": "db"}},
+			after:  map[string]any{},
+			want: []resource.FieldChange{
+				{Path: "/authentication/hostname", Op: resource.FieldRemoved, Before: `"db"`},
+				{Path: "/tags", Op: resource.FieldRemoved, Before: "{}"},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := resource.DiffPayloads(schema, tc.before, tc.after)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
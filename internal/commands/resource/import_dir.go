@@ -0,0 +1,308 @@
+package resource
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/massdriver-cloud/mass/internal/files"
+	"github.com/massdriver-cloud/mass/internal/jsonschema"
+	"github.com/massdriver-cloud/mass/internal/prettylogs"
+	"github.com/massdriver-cloud/mass/internal/resourcetype"
+	"github.com/massdriver-cloud/massdriver-sdk-go/massdriver/platform/resources"
+	"github.com/massdriver-cloud/massdriver-sdk-go/massdriver/platform/types"
+)
+
+// ImportFile is a resource file read by RunImportDir. The name and type header
+// identify the resource; payload is its data.
+type ImportFile struct {
+	Name    string         `json:"name"`
+	Type    string         `json:"type"`
+	Payload map[string]any `json:"payload"`
+}
+
+// ImportAction is what applying an import plan does with a resource file.
+type ImportAction string
+
+const (
+	// ImportCreate creates a resource that doesn't exist yet.
+	ImportCreate ImportAction = "create"
+	// ImportUpdate updates an existing resource whose payload changed.
+	ImportUpdate ImportAction = "update"
+	// ImportUnchanged leaves an existing resource that already matches alone.
+	ImportUnchanged ImportAction = "unchanged"
+)
+
+// ImportPlanItem is the planned import of one resource file. Err is set when the
+// file can't be imported.
+type ImportPlanItem struct {
+	File       string
+	Name       string
+	Type       string
+	Action     ImportAction
+	ResourceID string
+	Payload    map[string]any
+	Changes    []FieldChange
+	Err        error
+}
+
+// ImportDirOptions controls RunImportDir.
+type ImportDirOptions struct {
+	// DryRun prints the plan without applying it.
+	DryRun bool
+	// Yes applies the plan without asking for confirmation.
+	Yes bool
+	// In is read for the confirmation. Defaults to stdin.
+	In io.Reader
+	// Out receives the plan and results. Defaults to stdout.
+	Out io.Writer
+}
+
+// RunImportDir imports every JSON and YAML resource file under dir. Resources are
+// keyed by name within their type, so a file is created if no resource of its type
+// has its name, updated if its payload changed, and otherwise left alone; running it
+// again after a successful import changes nothing. It plans every file first and only
+// applies the plan, after confirmation, when every file is valid.
+func RunImportDir(ctx context.Context, api API, dir string, opts ImportDirOptions) ([]ImportPlanItem, error) {
+	if opts.In == nil {
+		opts.In = os.Stdin
+	}
+	if opts.Out == nil {
+		opts.Out = os.Stdout
+	}
+
+	plan, planErr := PlanImportDir(ctx, api, dir)
+	if planErr != nil {
+		return nil, planErr
+	}
+	printImportPlan(opts.Out, plan)
+
+	invalid := 0
+	pending := 0
+	for _, item := range plan {
+		switch {
+		case item.Err != nil:
+			invalid++
+		case item.Action != ImportUnchanged:
+			pending++
+		}
+	}
+	if invalid > 0 {
+		return plan, fmt.Errorf("%d resource file(s) can't be imported, nothing was applied", invalid)
+	}
+	if pending == 0 {
+		fmt.Fprintln(opts.Out, "All resources are up to date, nothing to import.")
+		return plan, nil
+	}
+	if opts.DryRun {
+		fmt.Fprintln(opts.Out, "Dry run: no resources were changed.")
+		return plan, nil
+	}
+
+	if !opts.Yes {
+		fmt.Fprint(opts.Out, "Apply this plan? (y/N): ")
+		reader := bufio.NewReader(opts.In)
+		answer, _ := reader.ReadString('\n')
+		answer = strings.TrimSpace(strings.ToLower(answer))
+		if answer != "y" && answer != "yes" {
+			fmt.Fprintln(opts.Out, "Import cancelled.")
+			return plan, nil
+		}
+	}
+
+	for i, item := range plan {
+		switch item.Action {
+		case ImportCreate:
+			created, createErr := api.CreateResource(ctx, item.Type, resources.CreateInput{Name: item.Name, Payload: item.Payload})
+			if createErr != nil {
+				return plan, fmt.Errorf("failed to create resource %s from %s: %w", item.Name, item.File, createErr)
+			}
+			plan[i].ResourceID = created.ID
+			fmt.Fprintf(opts.Out, "%s Created %s (Resource ID: %s)\n", prettylogs.Green("✓"), item.Name, created.ID)
+		case ImportUpdate:
+			if _, updateErr := api.UpdateResource(ctx, item.ResourceID, resources.UpdateInput{Name: item.Name, Payload: item.Payload}); updateErr != nil {
+				return plan, fmt.Errorf("failed to update resource %s from %s: %w", item.Name, item.File, updateErr)
+			}
+			fmt.Fprintf(opts.Out, "%s Updated %s (Resource ID: %s)\n", prettylogs.Green("✓"), item.Name, item.ResourceID)
+		case ImportUnchanged:
+		}
+	}
+	fmt.Fprintf(opts.Out, "Imported %d resource(s).\n", pending)
+	return plan, nil
+}
+
+// importExtensions are the extensions of the files PlanImportDir reads.
+var importExtensions = []string{".json", ".yaml", ".yml"}
+
+// PlanImportDir reads and validates every JSON and YAML resource file under dir,
+// skipping hidden files and directories, and plans what importing each one does.
+// Problems with a file are recorded on its item rather than returned.
+func PlanImportDir(ctx context.Context, api API, dir string) ([]ImportPlanItem, error) {
+	paths := []string{}
+	walkErr := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != dir && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() && slices.Contains(importExtensions, filepath.Ext(path)) {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if walkErr != nil {
+		return nil, walkErr
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no JSON or YAML resource files found in %s", dir)
+	}
+
+	planner := importPlanner{
+		api:      api,
+		types:    map[string]*resourcetype.ResourceType{},
+		existing: map[string]map[string]types.Resource{},
+		seen:     map[string]string{},
+	}
+	plan := make([]ImportPlanItem, 0, len(paths))
+	for _, path := range paths {
+		relativePath, relErr := filepath.Rel(dir, path)
+		if relErr != nil {
+			return nil, relErr
+		}
+		item := ImportPlanItem{File: filepath.ToSlash(relativePath)}
+		planner.plan(ctx, path, &item)
+		plan = append(plan, item)
+	}
+	return plan, nil
+}
+
+// importPlanner caches the resource types and existing resources
+// looked up while planning, so each type is fetched once however many files use it.
+type importPlanner struct {
+	api      API
+	types    map[string]*resourcetype.ResourceType
+	existing map[string]map[string]types.Resource
+	// seen maps type/name keys to the file that first declared them
+	seen map[string]string
+}
+
+func (p *importPlanner) plan(ctx context.Context, path string, item *ImportPlanItem) {
+	var file ImportFile
+	if readErr := files.Read(path, &file); readErr != nil {
+		item.Err = fmt.Errorf("failed to read resource file: %w", readErr)
+		return
+	}
+	item.Name = file.Name
+	item.Type = file.Type
+	item.Payload = file.Payload
+	if file.Name == "" || file.Type == "" {
+		item.Err = errors.New("resource file must declare a name and a type")
+		return
+	}
+	key := file.Type + "/" + file.Name
+	if first, dup := p.seen[key]; dup {
+		item.Err = fmt.Errorf("resource %s of type %s is also declared in %s", file.Name, file.Type, first)
+		return
+	}
+	p.seen[key] = item.File
+
+	rt, typeErr := p.resourceType(ctx, file.Type)
+	if typeErr != nil {
+		item.Err = typeErr
+		return
+	}
+	sch, schemaErr := jsonschema.LoadSchemaFromGo(rt.Schema)
+	if schemaErr != nil {
+		item.Err = fmt.Errorf("failed to compile resource definition schema: %w", schemaErr)
+		return
+	}
+	if validateErr := jsonschema.ValidateGo(sch, file.Payload); validateErr != nil {
+		item.Err = fmt.Errorf("payload isn't a valid %s: %s", rt.Name, strings.Join(jsonschema.ValidationMessages(validateErr), "; "))
+		return
+	}
+
+	existing, listErr := p.existingResources(ctx, file.Type)
+	if listErr != nil {
+		item.Err = listErr
+		return
+	}
+	current, found := existing[file.Name]
+	if !found {
+		item.Action = ImportCreate
+		return
+	}
+	item.ResourceID = current.ID
+	item.Changes = DiffPayloads(rt.Schema, current.Payload, file.Payload)
+	if len(item.Changes) == 0 {
+		item.Action = ImportUnchanged
+		return
+	}
+	item.Action = ImportUpdate
+}
+
+func (p *importPlanner) resourceType(ctx context.Context, name string) (*resourcetype.ResourceType, error) {
+	if rt, ok := p.types[name]; ok {
+		return rt, nil
+	}
+	rt, typeErr := p.api.GetResourceType(ctx, name)
+	if typeErr != nil {
+		return nil, fmt.Errorf("failed to get resource type %s: %w", name, typeErr)
+	}
+	p.types[name] = rt
+	return rt, nil
+}
+
+func (p *importPlanner) existingResources(ctx context.Context, resourceType string) (map[string]types.Resource, error) {
+	if byName, ok := p.existing[resourceType]; ok {
+		return byName, nil
+	}
+	listed, listErr := p.api.ListResources(ctx, resources.ListInput{ResourceType: resourceType})
+	if listErr != nil {
+		return nil, fmt.Errorf("failed to list resources of type %s: %w", resourceType, listErr)
+	}
+	byName := make(map[string]types.Resource, len(listed))
+	for _, res := range listed {
+		byName[res.Name] = res
+	}
+	p.existing[resourceType] = byName
+	return byName, nil
+}
+
+func printImportPlan(out io.Writer, plan []ImportPlanItem) {
+	counts := map[ImportAction]int{}
+	invalid := 0
+	for _, item := range plan {
+		if item.Err != nil {
+			invalid++
+			fmt.Fprintf(out, "%s %s: %v\n", prettylogs.Red("✗"), item.File, item.Err)
+			continue
+		}
+		counts[item.Action]++
+		label := fmt.Sprintf("%s (%s) from %s", item.Name, item.Type, item.File)
+		switch item.Action {
+		case ImportCreate:
+			fmt.Fprintln(out, prettylogs.Green("  + create "+label))
+		case ImportUpdate:
+			fmt.Fprintln(out, prettylogs.Orange("  ~ update "+label))
+			PrintFieldChanges(out, "      ", item.Changes)
+		case ImportUnchanged:
+			fmt.Fprintf(out, "  = unchanged %s\n", label)
+		}
+	}
+	fmt.Fprintf(out, "\nPlan: %d to create, %d to update, %d unchanged", counts[ImportCreate], counts[ImportUpdate], counts[ImportUnchanged])
+	if invalid > 0 {
+		fmt.Fprintf(out, ", %d invalid", invalid)
+	}
+	fmt.Fprintln(out)
+}
@@ -0,0 +1,179 @@
+package resource_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/massdriver-cloud/mass/internal/commands/resource"
+	"github.com/massdriver-cloud/mass/internal/resourcetype"
+
+	"github.com/massdriver-cloud/massdriver-sdk-go/massdriver/platform/types"
+)
+
+var importResourceType = &resourcetype.ResourceType{
+	ID:   "massdriver/fake-resource-schema",
+	Name: "massdriver/fake-resource-schema",
+	Schema: map[string]any{
+		"type":     "object",
+		"required": []any{"name"},
+		"properties": map[string]any{
+			"name": map[string]any{"type": "string"},
+		},
+	},
+}
+
+func TestRunImportDir(t *testing.T) {
+	type test struct {
+		name        string
+		existing    []types.Resource
+		opts        resource.ImportDirOptions
+		wantActions map[string]resource.ImportAction
+		wantCreated []string
+		wantUpdated []string
+		wantOutput  string
+	}
+	tests := []test{
+		{
+			name: "creates new resources",
+			opts: resource.ImportDirOptions{Yes: true},
+			wantActions: map[string]resource.ImportAction{
+				"roles/deploy.yml":  resource.ImportCreate,
+				"vpcs/prod.yaml":    resource.ImportCreate,
+				"vpcs/staging.json": resource.ImportCreate,
+			},
+			wantCreated: []string{"deploy-role", "prod-vpc", "staging-vpc"},
+			wantOutput:  "Plan: 3 to create, 0 to update, 0 unchanged",
+		},
+		{
+			name: "updates changed resources by name and leaves matching ones alone",
+			existing: []types.Resource{
+				{ID: "role-id", Name: "deploy-role", Payload: map[string]any{"name": "old"}},
+				{ID: "staging-id", Name: "staging-vpc", Payload: map[string]any{"name": "staging"}},
+			},
+			opts: resource.ImportDirOptions{Yes: true},
+			wantActions: map[string]resource.ImportAction{
+				"roles/deploy.yml":  resource.ImportUpdate,
+				"vpcs/prod.yaml":    resource.ImportCreate,
+				"vpcs/staging.json": resource.ImportUnchanged,
+			},
+			wantCreated: []string{"prod-vpc"},
+			wantUpdated: []string{"role-id"},
+			wantOutput:  `~ /name: "old" => "deployer"`,
+		},
+		{
+			name: "nothing to import",
+			existing: []types.Resource{
+				{ID: "role-id", Name: "deploy-role", Payload: map[string]any{"name": "deployer"}},
+				{ID: "prod-id", Name: "prod-vpc", Payload: map[string]any{"name": "prod"}},
+				{ID: "staging-id", Name: "staging-vpc", Payload: map[string]any{"name": "staging"}},
+			},
+			wantActions: map[string]resource.ImportAction{
+				"roles/deploy.yml":  resource.ImportUnchanged,
+				"vpcs/prod.yaml":    resource.ImportUnchanged,
+				"vpcs/staging.json": resource.ImportUnchanged,
+			},
+			wantOutput: "nothing to import",
+		},
+		{
+			name: "dry run",
+			opts: resource.ImportDirOptions{DryRun: true},
+			wantActions: map[string]resource.ImportAction{
+				"roles/deploy.yml":  resource.ImportCreate,
+				"vpcs/prod.yaml":    resource.ImportCreate,
+				"vpcs/staging.json": resource.ImportCreate,
+			},
+			wantOutput: "Dry run: no resources were changed.",
+		},
+		{
+			name: "confirmation declined",
+			opts: resource.ImportDirOptions{In: strings.NewReader("n\n")},
+			wantActions: map[string]resource.ImportAction{
+				"roles/deploy.yml":  resource.ImportCreate,
+				"vpcs/prod.yaml":    resource.ImportCreate,
+				"vpcs/staging.json": resource.ImportCreate,
+			},
+			wantOutput: "Import cancelled.",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			api := &fakeResourceAPI{
+				resource:     &types.Resource{ID: "created-id"},
+				resourceType: importResourceType,
+				resources:    tc.existing,
+			}
+			var out bytes.Buffer
+			tc.opts.Out = &out
+
+			plan, err := resource.RunImportDir(t.Context(), api, "testdata/import-dir", tc.opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			gotActions := map[string]resource.ImportAction{}
+			for _, item := range plan {
+				gotActions[item.File] = item.Action
+			}
+			if !reflect.DeepEqual(gotActions, tc.wantActions) {
+				t.Errorf("got actions %v, want %v", gotActions, tc.wantActions)
+			}
+			if !reflect.DeepEqual(api.gotCreateNames, tc.wantCreated) {
+				t.Errorf("got created %v, want %v", api.gotCreateNames, tc.wantCreated)
+			}
+			if !reflect.DeepEqual(api.gotUpdateIDs, tc.wantUpdated) {
+				t.Errorf("got updated %v, want %v", api.gotUpdateIDs, tc.wantUpdated)
+			}
+			if !strings.Contains(out.String(), tc.wantOutput) {
+				t.Errorf("expected output to contain %q, got:\n%s", tc.wantOutput, out.String())
+			}
+		})
+	}
+}
+
+func TestRunImportDirInvalid(t *testing.T) {
+	dir := t.TempDir()
+	resourceFiles := map[string]string{
+		"a.yaml":         "name: a\ntype: massdriver/fake-resource-schema\npayload:\n  name: a\n",
+		"duplicate.json": `{"name": "a", "type": "massdriver/fake-resource-schema", "payload": {"name": "a"}}`,
+		"invalid.yaml":   "name: b\ntype: massdriver/fake-resource-schema\npayload:\n  name: 1\n",
+		"no-type.yaml":   "name: c\npayload:\n  name: c\n",
+	}
+	for name, contents := range resourceFiles {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	api := &fakeResourceAPI{resourceType: importResourceType}
+	var out bytes.Buffer
+	plan, err := resource.RunImportDir(t.Context(), api, dir, resource.ImportDirOptions{Yes: true, Out: &out})
+	if err == nil || err.Error() != "3 resource file(s) can't be imported, nothing was applied" {
+		t.Fatalf("got error %v, want 3 invalid files", err)
+	}
+
+	wantErrs := map[string]string{
+		"duplicate.json": "resource a of type massdriver/fake-resource-schema is also declared in a.yaml",
+		"invalid.yaml":   "payload isn't a valid massdriver/fake-resource-schema: at '/name': got number, want string",
+		"no-type.yaml":   "resource file must declare a name and a type",
+	}
+	for _, item := range plan {
+		want, wantErr := wantErrs[item.File]
+		switch {
+		case wantErr && (item.Err == nil || item.Err.Error() != want):
+			t.Errorf("%s: got error %v, want %q", item.File, item.Err, want)
+		case !wantErr && item.Err != nil:
+			t.Errorf("%s: unexpected error %v", item.File, item.Err)
+		}
+	}
+	if len(api.gotCreateNames) != 0 || len(api.gotUpdateIDs) != 0 {
+		t.Error("expected nothing to be applied when a file is invalid")
+	}
+	if !strings.Contains(out.String(), "Plan: 1 to create, 0 to update, 0 unchanged, 3 invalid") {
+		t.Errorf("expected the plan summary to count invalid files, got:\n%s", out.String())
+	}
+}
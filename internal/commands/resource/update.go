@@ -1,16 +1,23 @@
 package resource
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 
+	"github.com/massdriver-cloud/mass/internal/jsonschema"
 	"github.com/massdriver-cloud/massdriver-sdk-go/massdriver/platform/resources"
 )
 
-// RunUpdate updates an existing resource with the data from the given file.
-func RunUpdate(ctx context.Context, api API, resourceID string, resourceName string, resourceFile string) (string, error) {
+// RunUpdate updates an existing resource with the data from the given file. The new
+// payload is validated against the resource's type, and the changed fields are shown
+// (with sensitive values masked) and confirmed unless yes is set. It returns the
+// resource's ID, or an empty ID when nothing changed or the update was cancelled.
+func RunUpdate(ctx context.Context, api API, resourceID string, resourceName string, resourceFile string, yes bool, in io.Reader) (string, error) {
 	bytes, readErr := os.ReadFile(resourceFile)
 	if readErr != nil {
 		return "", readErr
@@ -22,15 +29,50 @@ func RunUpdate(ctx context.Context, api API, resourceID string, resourceName str
 		return "", unmarshalErr
 	}
 
-	// Name is required by the backend. If not provided, fetch the existing resource's name.
-	if resourceName == "" {
-		existing, getErr := api.GetResource(ctx, resourceID)
-		if getErr != nil {
-			return "", fmt.Errorf("failed to get existing resource: %w", getErr)
+	existing, getErr := api.GetResource(ctx, resourceID)
+	if getErr != nil {
+		return "", fmt.Errorf("failed to get existing resource: %w", getErr)
+	}
+	if existing.ResourceType == nil {
+		return "", fmt.Errorf("resource %s has no resource type to validate against", resourceID)
+	}
+
+	rt, validateErr := validateResource(ctx, api, existing.ResourceType.Name, payload)
+	if validateErr != nil {
+		if rt == nil {
+			return "", validateErr
 		}
+		return "", fmt.Errorf("payload isn't a valid %s: %s", rt.Name, strings.Join(jsonschema.ValidationMessages(validateErr), "; "))
+	}
+
+	// Name is required by the backend. If not provided, keep the existing resource's name.
+	if resourceName == "" {
 		resourceName = existing.Name
 	}
 
+	changes := DiffPayloads(rt.Schema, existing.Payload, payload)
+	if len(changes) == 0 && resourceName == existing.Name {
+		fmt.Printf("Resource %s is up to date, nothing to update.\n", existing.Name)
+		return "", nil
+	}
+
+	fmt.Printf("Resource %s (%s) will be updated:\n", existing.Name, resourceID)
+	if resourceName != existing.Name {
+		fmt.Printf("  name: %s => %s\n", existing.Name, resourceName)
+	}
+	PrintFieldChanges(os.Stdout, "  ", changes)
+
+	if !yes {
+		fmt.Print("Apply these changes? (y/N): ")
+		reader := bufio.NewReader(in)
+		answer, _ := reader.ReadString('\n')
+		answer = strings.TrimSpace(strings.ToLower(answer))
+		if answer != "y" && answer != "yes" {
+			fmt.Println("Update cancelled.")
+			return "", nil
+		}
+	}
+
 	input := resources.UpdateInput{
 		Name:    resourceName,
 		Payload: payload,
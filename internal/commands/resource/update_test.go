@@ -1,19 +1,43 @@
 package resource_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/massdriver-cloud/mass/internal/commands/resource"
+	"github.com/massdriver-cloud/mass/internal/resourcetype"
 
 	"github.com/massdriver-cloud/massdriver-sdk-go/massdriver/platform/types"
 )
 
+var updateResourceType = &resourcetype.ResourceType{
+	ID:   "massdriver/fake-resource-schema",
+	Name: "massdriver/fake-resource-schema",
+	Schema: map[string]any{
+		"$schema": "http://json-schema.org/draft-07/schema",
+		"type":    "object",
+		"properties": map[string]any{
+			"name": map[string]any{"type": "string"},
+		},
+	},
+}
+
+func existingResource(name string, payload map[string]any) *types.Resource {
+	return &types.Resource{
+		ID:           "resource-id",
+		Name:         name,
+		Payload:      payload,
+		ResourceType: &types.ResourceType{ID: "massdriver/fake-resource-schema", Name: "massdriver/fake-resource-schema"},
+	}
+}
+
 func TestResourceUpdate(t *testing.T) {
 	api := &fakeResourceAPI{
-		resource: &types.Resource{ID: "resource-id", Name: "resource-name"},
+		resource:     existingResource("resource-name", map[string]any{"name": "old"}),
+		resourceType: updateResourceType,
 	}
 
-	got, err := resource.RunUpdate(t.Context(), api, "resource-id", "resource-name", "testdata/resource.json")
+	got, err := resource.RunUpdate(t.Context(), api, "resource-id", "resource-name", "testdata/resource.json", true, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -27,12 +51,13 @@ func TestResourceUpdate(t *testing.T) {
 }
 
 func TestResourceUpdateWithoutName(t *testing.T) {
-	// When no name is provided, RunUpdate fetches the existing resource first.
+	// When no name is provided, RunUpdate keeps the existing resource's name.
 	api := &fakeResourceAPI{
-		resource: &types.Resource{ID: "resource-id", Name: "existing-name"},
+		resource:     existingResource("existing-name", map[string]any{"name": "old"}),
+		resourceType: updateResourceType,
 	}
 
-	got, err := resource.RunUpdate(t.Context(), api, "resource-id", "", "testdata/resource.json")
+	got, err := resource.RunUpdate(t.Context(), api, "resource-id", "", "testdata/resource.json", true, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -44,3 +69,63 @@ func TestResourceUpdateWithoutName(t *testing.T) {
 		t.Errorf("got name %q, wanted existing-name", api.gotUpdateInput.Name)
 	}
 }
+
+func TestResourceUpdateConfirmation(t *testing.T) {
+	type test struct {
+		name        string
+		payload     map[string]any
+		answer      string
+		wantUpdated bool
+	}
+	tests := []test{
+		{name: "confirmed", payload: map[string]any{"name": "old"}, answer: "y\n", wantUpdated: true},
+		{name: "declined", payload: map[string]any{"name": "old"}, answer: "n\n", wantUpdated: false},
+		{name: "nothing changed", payload: map[string]any{"name": "fake"}, answer: "y\n", wantUpdated: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			api := &fakeResourceAPI{
+				resource:     existingResource("resource-name", tc.payload),
+				resourceType: updateResourceType,
+			}
+
+			got, err := resource.RunUpdate(t.Context(), api, "resource-id", "", "testdata/resource.json", false, strings.NewReader(tc.answer))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if updated := api.gotUpdateID != ""; updated != tc.wantUpdated {
+				t.Errorf("got updated %t, wanted %t", updated, tc.wantUpdated)
+			}
+			if tc.wantUpdated != (got != "") {
+				t.Errorf("got ID %q with updated %t", got, tc.wantUpdated)
+			}
+		})
+	}
+}
+
+func TestResourceUpdateInvalidPayload(t *testing.T) {
+	api := &fakeResourceAPI{
+		resource: existingResource("resource-name", map[string]any{}),
+		resourceType: &resourcetype.ResourceType{
+			Name: "massdriver/fake-resource-schema",
+			Schema: map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"name": map[string]any{"type": "integer"}},
+			},
+		},
+	}
+
+	_, err := resource.RunUpdate(t.Context(), api, "resource-id", "", "testdata/resource.json", true, nil)
+	if err == nil {
+		t.Fatal("expected a validation error")
+	}
+	want := "payload isn't a valid massdriver/fake-resource-schema: at '/name': got string, want integer"
+	if err.Error() != want {
+		t.Errorf("got error %q, wanted %q", err, want)
+	}
+	if api.gotUpdateID != "" {
+		t.Error("expected an invalid payload not to be updated")
+	}
+}
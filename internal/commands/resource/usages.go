@@ -0,0 +1,96 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// UsageKind is how an environment or instance depends on a resource.
+type UsageKind string
+
+const (
+	// UsageEnvironmentDefault is an environment that uses the resource as its default of that type.
+	UsageEnvironmentDefault UsageKind = "environment default"
+	// UsageConnection is an instance connected to the resource.
+	UsageConnection UsageKind = "connection"
+	// UsageRemoteReference is an instance with a remote reference to the resource.
+	UsageRemoteReference UsageKind = "remote reference"
+)
+
+// Usage is an environment or instance that depends on a resource. InstanceID and
+// Field are empty for environment defaults.
+type Usage struct {
+	Kind            UsageKind
+	EnvironmentID   string
+	EnvironmentName string
+	InstanceID      string
+	InstanceName    string
+	Field           string
+}
+
+// FindUsages walks every environment's defaults and every instance's connections and
+// remote references, returning each one that refers to resourceID.
+func FindUsages(ctx context.Context, api API, resourceID string) ([]Usage, error) {
+	envs, listErr := api.ListEnvironmentResourceRefs(ctx)
+	if listErr != nil {
+		return nil, fmt.Errorf("failed to list environments: %w", listErr)
+	}
+
+	usages := []Usage{}
+	for _, env := range envs {
+		for _, def := range env.Defaults {
+			if def.ID == resourceID {
+				usages = append(usages, Usage{Kind: UsageEnvironmentDefault, EnvironmentID: env.ID, EnvironmentName: env.Name})
+			}
+		}
+		for _, inst := range env.Instances {
+			instanceUsage := Usage{EnvironmentID: env.ID, EnvironmentName: env.Name, InstanceID: inst.ID, InstanceName: inst.Name}
+			for _, conn := range inst.Connections {
+				if conn.Resource != nil && conn.Resource.ID == resourceID {
+					instanceUsage.Kind, instanceUsage.Field = UsageConnection, conn.Field
+					usages = append(usages, instanceUsage)
+				}
+			}
+			for _, ref := range inst.RemoteReferences {
+				if ref.Resource != nil && ref.Resource.ID == resourceID {
+					instanceUsage.Kind, instanceUsage.Field = UsageRemoteReference, ref.Field
+					usages = append(usages, instanceUsage)
+				}
+			}
+		}
+	}
+	return usages, nil
+}
+
+// PrintUsages writes one line per usage to out.
+func PrintUsages(out io.Writer, usages []Usage) {
+	for _, usage := range usages {
+		if usage.InstanceID == "" {
+			fmt.Fprintf(out, "  - environment %s (%s): %s\n", usage.EnvironmentName, usage.EnvironmentID, usage.Kind)
+			continue
+		}
+		fmt.Fprintf(out, "  - instance %s (%s) in environment %s: %s on field %s\n", usage.InstanceName, usage.InstanceID, usage.EnvironmentName, usage.Kind, usage.Field)
+	}
+}
+
+// RunUsages lists every environment and instance that depends on a resource.
+func RunUsages(ctx context.Context, api API, resourceID string, out io.Writer) ([]Usage, error) {
+	res, getErr := api.GetResource(ctx, resourceID)
+	if getErr != nil {
+		return nil, fmt.Errorf("error getting resource: %w", getErr)
+	}
+
+	usages, findErr := FindUsages(ctx, api, resourceID)
+	if findErr != nil {
+		return nil, findErr
+	}
+	if len(usages) == 0 {
+		fmt.Fprintf(out, "Resource %s isn't used by any environment or instance.\n", res.Name)
+		return usages, nil
+	}
+
+	fmt.Fprintf(out, "Resource %s is used by:\n", res.Name)
+	PrintUsages(out, usages)
+	return usages, nil
+}
@@ -0,0 +1,99 @@
+package resource_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/massdriver-cloud/mass/internal/api"
+	"github.com/massdriver-cloud/mass/internal/commands/resource"
+
+	"github.com/massdriver-cloud/massdriver-sdk-go/massdriver/platform/types"
+)
+
+var usageEnvironments = []api.EnvironmentResourceRefs{
+	{
+		ID:       "ecomm-prod",
+		Name:     "production",
+		Defaults: []api.ResourceRef{{ID: "vpc-id"}, {ID: "dns-id"}},
+		Instances: []api.InstanceResourceRefs{
+			{
+				ID:          "ecomm-prod-db",
+				Name:        "db",
+				Connections: []api.FieldRef{{Field: "network", Resource: &api.ResourceRef{ID: "vpc-id"}}, {Field: "dns", Resource: &api.ResourceRef{ID: "dns-id"}}},
+			},
+			{
+				ID:               "ecomm-prod-app",
+				Name:             "app",
+				Connections:      []api.FieldRef{{Field: "database", Resource: nil}},
+				RemoteReferences: []api.FieldRef{{Field: "network", Resource: &api.ResourceRef{ID: "vpc-id"}}},
+			},
+		},
+	},
+	{
+		ID:   "ecomm-staging",
+		Name: "staging",
+		Instances: []api.InstanceResourceRefs{
+			{ID: "ecomm-staging-db", Name: "db", Connections: []api.FieldRef{{Field: "network", Resource: &api.ResourceRef{ID: "staging-vpc-id"}}}},
+		},
+	},
+}
+
+func TestFindUsages(t *testing.T) {
+	fake := &fakeResourceAPI{environments: usageEnvironments}
+
+	got, err := resource.FindUsages(t.Context(), fake, "vpc-id")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []resource.Usage{
+		{Kind: resource.UsageEnvironmentDefault, EnvironmentID: "ecomm-prod", EnvironmentName: "production"},
+		{Kind: resource.UsageConnection, EnvironmentID: "ecomm-prod", EnvironmentName: "production", InstanceID: "ecomm-prod-db", InstanceName: "db", Field: "network"},
+		{Kind: resource.UsageRemoteReference, EnvironmentID: "ecomm-prod", EnvironmentName: "production", InstanceID: "ecomm-prod-app", InstanceName: "app", Field: "network"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestRunUsages(t *testing.T) {
+	type test struct {
+		name       string
+		resourceID string
+		wantOutput []string
+	}
+	tests := []test{
+		{
+			name:       "used",
+			resourceID: "dns-id",
+			wantOutput: []string{
+				"Resource my-resource is used by:",
+				"  - environment production (ecomm-prod): environment default",
+				"  - instance db (ecomm-prod-db) in environment production: connection on field dns",
+			},
+		},
+		{
+			name:       "unused",
+			resourceID: "unused-id",
+			wantOutput: []string{"Resource my-resource isn't used by any environment or instance."},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fake := &fakeResourceAPI{
+				resource:     &types.Resource{ID: tc.resourceID, Name: "my-resource"},
+				environments: usageEnvironments,
+			}
+			var out bytes.Buffer
+			if _, err := resource.RunUsages(t.Context(), fake, tc.resourceID, &out); err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.TrimSpace(out.String()); got != strings.Join(tc.wantOutput, "\n") {
+				t.Errorf("got output:\n%s\nwant:\n%s", got, strings.Join(tc.wantOutput, "\n"))
+			}
+		})
+	}
+}
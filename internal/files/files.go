@@ -3,12 +3,17 @@ package files
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 
 	"github.com/BurntSushi/toml"
 	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
 	ctyjson "github.com/zclconf/go-cty/cty/json"
 	"sigs.k8s.io/yaml"
 )
@@ -18,21 +23,31 @@ const UserRW = 0600
 
 // Write serializes data and writes it to path using the format inferred from the file extension.
 func Write(path string, data any) error {
-	var formattedData []byte
-	ext := filepath.Ext(path)
+	formattedData, err := Marshal(filepath.Ext(path), data)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, formattedData, UserRW)
+}
 
+// Marshal serializes data in the format of the given file extension: .json, .yaml or
+// .tfvars. Output is readable by Read from a file with the same extension.
+func Marshal(ext string, data any) ([]byte, error) {
 	switch ext {
 	case ".json":
 		json, err := json.MarshalIndent(data, "", "  ")
-		formattedData = json
 		if err != nil {
-			return err
+			return nil, err
 		}
+		return append(json, '\n'), nil
+	case ".yaml":
+		return yaml.Marshal(data)
+	case ".tfvars":
+		return encodeTFVars(data)
 	default:
-		return fmt.Errorf("unsupported file type: %s", ext)
+		return nil, fmt.Errorf("unsupported file type: %s", ext)
 	}
-
-	return os.WriteFile(path, formattedData, UserRW)
 }
 
 // Read reads and deserializes the file at path into v using the format inferred from the file extension.
@@ -53,7 +68,7 @@ func Read(path string, v any) error {
 		if _, err = toml.Decode(string(contents), v); err != nil {
 			return err
 		}
-	case ".yaml":
+	case ".yaml", ".yml":
 		if err = yaml.Unmarshal(contents, &v); err != nil {
 			return err
 		}
@@ -115,3 +130,32 @@ func decodeTFVars(path string, v any) error {
 
 	return nil
 }
+
+func encodeTFVars(data any) ([]byte, error) {
+	jsonBytes, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal to JSON: %w", err)
+	}
+	impliedType, err := ctyjson.ImpliedType(jsonBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to infer type: %w", err)
+	}
+	if !impliedType.IsObjectType() {
+		return nil, errors.New("tfvars can only hold an object")
+	}
+	val, err := ctyjson.Unmarshal(jsonBytes, impliedType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert to HCL values: %w", err)
+	}
+
+	file := hclwrite.NewEmptyFile()
+	body := file.Body()
+	names := slices.Sorted(maps.Keys(impliedType.AttributeTypes()))
+	for _, name := range names {
+		if !hclsyntax.ValidIdentifier(name) {
+			return nil, fmt.Errorf("%q is not a valid tfvars variable name", name)
+		}
+		body.SetAttributeValue(name, val.GetAttr(name))
+	}
+	return file.Bytes(), nil
+}
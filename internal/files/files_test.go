@@ -217,3 +217,29 @@ func TestRead_FileNotFound(t *testing.T) {
 		t.Fatal("Expected error for nonexistent file, got nil")
 	}
 }
+
+func TestWrite_RoundTrip(t *testing.T) {
+	data := map[string]interface{}{
+		"name":    "test",
+		"value":   float64(42),
+		"enabled": true,
+		"nested":  map[string]interface{}{"tags": []interface{}{"a", "b"}},
+	}
+
+	for _, ext := range []string{".json", ".yaml", ".tfvars"} {
+		t.Run(ext, func(t *testing.T) {
+			path := t.TempDir() + "/params" + ext
+			if err := Write(path, data); err != nil {
+				t.Fatalf("Failed to write %s: %v", path, err)
+			}
+
+			var result map[string]interface{}
+			if err := Read(path, &result); err != nil {
+				t.Fatalf("Failed to read %s: %v", path, err)
+			}
+			if !reflect.DeepEqual(result, data) {
+				t.Errorf("round trip mismatch: got %v, want %v", result, data)
+			}
+		})
+	}
+}
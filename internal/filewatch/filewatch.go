@@ -0,0 +1,116 @@
+// Package filewatch detects changes to sets of files and directories by polling
+// their modification times and sizes.
+package filewatch
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Snapshot records the modification time and size of every watched file. Files that
+// don't exist are absent, so creating one shows up as a change.
+type Snapshot map[string]FileState
+
+// FileState is the part of a file's metadata compared between snapshots.
+type FileState struct {
+	ModTime time.Time
+	Size    int64
+}
+
+// Take snapshots the given paths. Directories are walked recursively, skipping hidden
+// directories such as .terraform and .git.
+func Take(paths []string) Snapshot {
+	snap := Snapshot{}
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if !info.IsDir() {
+			snap[path] = FileState{ModTime: info.ModTime(), Size: info.Size()}
+			continue
+		}
+		//nolint:errcheck // files that vanish mid-walk are picked up by the next snapshot
+		filepath.WalkDir(path, func(walked string, entry fs.DirEntry, walkErr error) error {
+			if walkErr != nil {
+				return nil //nolint:nilerr // unreadable entries are skipped, not fatal
+			}
+			if entry.IsDir() {
+				if walked != path && strings.HasPrefix(entry.Name(), ".") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if entryInfo, infoErr := entry.Info(); infoErr == nil {
+				snap[walked] = FileState{ModTime: entryInfo.ModTime(), Size: entryInfo.Size()}
+			}
+			return nil
+		})
+	}
+	return snap
+}
+
+// Changed returns the sorted paths that were added, removed or modified between s and next.
+func (s Snapshot) Changed(next Snapshot) []string {
+	changed := []string{}
+	for path, state := range next {
+		if previous, existed := s[path]; !existed || !previous.ModTime.Equal(state.ModTime) || previous.Size != state.Size {
+			changed = append(changed, path)
+		}
+	}
+	for path := range s {
+		if _, exists := next[path]; !exists {
+			changed = append(changed, path)
+		}
+	}
+	slices.Sort(changed)
+	return changed
+}
+
+// Options controls how Wait polls.
+type Options struct {
+	// Interval is how often the watched paths are polled.
+	Interval time.Duration
+	// Debounce is how long the paths must stay unchanged after a change before Wait
+	// returns, so a burst of saves is reported once.
+	Debounce time.Duration
+}
+
+// Wait polls paths until they differ from baseline and then settle, returning every
+// path that changed in the meantime. It returns ctx's error if ctx is done first.
+func Wait(ctx context.Context, paths []string, baseline Snapshot, opts Options) ([]string, error) {
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+
+	current := baseline
+	changed := []string{}
+	var lastChange time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+
+		next := Take(paths)
+		if diff := current.Changed(next); len(diff) > 0 {
+			for _, path := range diff {
+				if !slices.Contains(changed, path) {
+					changed = append(changed, path)
+				}
+			}
+			current = next
+			lastChange = time.Now()
+			continue
+		}
+		if len(changed) > 0 && time.Since(lastChange) >= opts.Debounce {
+			slices.Sort(changed)
+			return changed, nil
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package filewatch_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/massdriver-cloud/mass/internal/filewatch"
+)
+
+func TestChanged(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "massdriver.yaml"), "name: a")
+	writeFile(t, filepath.Join(dir, "src", "main.tf"), "")
+	writeFile(t, filepath.Join(dir, "src", "gone.tf"), "")
+	writeFile(t, filepath.Join(dir, "src", ".terraform", "lock"), "")
+	paths := []string{filepath.Join(dir, "massdriver.yaml"), filepath.Join(dir, "src"), filepath.Join(dir, "schema.json")}
+
+	before := filewatch.Take(paths)
+	if _, hidden := before[filepath.Join(dir, "src", ".terraform", "lock")]; hidden {
+		t.Errorf("hidden directories should not be watched")
+	}
+
+	writeFile(t, filepath.Join(dir, "massdriver.yaml"), "name: abc")
+	writeFile(t, filepath.Join(dir, "schema.json"), "{}")
+	writeFile(t, filepath.Join(dir, "src", ".terraform", "lock"), "changed")
+	if err := os.Remove(filepath.Join(dir, "src", "gone.tf")); err != nil {
+		t.Fatal(err)
+	}
+
+	got := before.Changed(filewatch.Take(paths))
+	want := []string{
+		filepath.Join(dir, "massdriver.yaml"),
+		filepath.Join(dir, "schema.json"),
+		filepath.Join(dir, "src", "gone.tf"),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestWait(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "massdriver.yaml")
+	writeFile(t, path, "name: a")
+	opts := filewatch.Options{Interval: 10 * time.Millisecond, Debounce: 30 * time.Millisecond}
+
+	t.Run("returns changed paths once they settle", func(t *testing.T) {
+		baseline := filewatch.Take([]string{path})
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			writeFile(t, path, "name: ab")
+		}()
+
+		ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+		defer cancel()
+		got, err := filewatch.Wait(ctx, []string{path}, baseline, opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(got, []string{path}) {
+			t.Errorf("got %v, want %v", got, []string{path})
+		}
+	})
+
+	t.Run("stops when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+		defer cancel()
+		_, err := filewatch.Wait(ctx, []string{path}, filewatch.Take([]string{path}), opts)
+		if err == nil {
+			t.Errorf("expected an error once the context is done")
+		}
+	})
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,226 @@
+package jsonschema
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Draft is a JSON Schema draft that Convert can translate between.
+type Draft string
+
+const (
+	// Draft07 is JSON Schema draft-07, the draft Massdriver bundles and resource types use.
+	Draft07 Draft = "draft-07"
+	// Draft202012 is JSON Schema 2020-12.
+	Draft202012 Draft = "2020-12"
+)
+
+var draftURIs = map[Draft]string{
+	Draft07:     "http://json-schema.org/draft-07/schema#",
+	Draft202012: "https://json-schema.org/draft/2020-12/schema",
+}
+
+// draft202012Only are keywords with no draft-07 equivalent, which Convert refuses to
+// translate rather than silently drop.
+var draft202012Only = []string{"$dynamicRef", "$dynamicAnchor", "$recursiveRef", "$recursiveAnchor", "unevaluatedProperties", "unevaluatedItems", "minContains", "maxContains"}
+
+// Convert translates a JSON or YAML schema document between file formats and, when
+// draft is set, between JSON Schema drafts. Only mechanical rewrites are made:
+// definitions and $defs, tuple items and prefixItems, dependencies and
+// dependentRequired/dependentSchemas, and plain-name $id fragments and $anchor.
+// Converting to draft-07 fails on keywords draft-07 can't express. A document without
+// $schema is taken to be draft-07. from and to are file extensions as for Format.
+func Convert(data []byte, from, to string, draft Draft) ([]byte, error) {
+	root, parseErr := parseDocument(data)
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	if draft != "" {
+		if err := convertDraft(root, draft); err != nil {
+			return nil, err
+		}
+	}
+	return encodeDocument(root, from, to)
+}
+
+func draftFromURI(uri string) (Draft, error) {
+	switch {
+	case uri == "", strings.Contains(uri, "draft-07"):
+		return Draft07, nil
+	case strings.Contains(uri, "2020-12"):
+		return Draft202012, nil
+	default:
+		return "", fmt.Errorf("unsupported $schema %q, only %s and %s can be converted", uri, Draft07, Draft202012)
+	}
+}
+
+func convertDraft(root *yaml.Node, draft Draft) error {
+	target, known := draftURIs[draft]
+	if !known {
+		return fmt.Errorf("unsupported draft %q, must be %s or %s", draft, Draft07, Draft202012)
+	}
+	var uri string
+	if schemaNode := mappingValue(root, "$schema"); schemaNode != nil {
+		uri = schemaNode.Value
+	}
+	source, sourceErr := draftFromURI(uri)
+	if sourceErr != nil {
+		return sourceErr
+	}
+	if source == draft {
+		return nil
+	}
+
+	upgrade := draft == Draft202012
+	walkErr := walkSchemas(root, "", func(schema *yaml.Node, pointer string) error {
+		rewriteRef(schema, upgrade)
+		if upgrade {
+			upgradeSchema(schema)
+			return nil
+		}
+		return downgradeSchema(schema, pointer)
+	})
+	if walkErr != nil {
+		return walkErr
+	}
+
+	if schemaNode := mappingValue(root, "$schema"); schemaNode != nil {
+		schemaNode.Value = target
+	} else {
+		root.Content = append([]*yaml.Node{
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: "$schema"},
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: target},
+		}, root.Content...)
+	}
+	return nil
+}
+
+// upgradeSchema rewrites the draft-07 keywords of one schema to their 2020-12 form.
+func upgradeSchema(schema *yaml.Node) {
+	renameKey(schema, "definitions", "$defs")
+
+	if items := mappingValue(schema, "items"); items != nil && items.Kind == yaml.SequenceNode {
+		// additionalItems must become items only after the tuple form moves out of it
+		renameKey(schema, "items", "prefixItems")
+		renameKey(schema, "additionalItems", "items")
+	}
+
+	if id := mappingValue(schema, "$id"); id != nil && strings.HasPrefix(id.Value, "#") {
+		renameKey(schema, "$id", "$anchor")
+		id.Value = strings.TrimPrefix(id.Value, "#")
+	}
+
+	if deps := mappingValue(schema, "dependencies"); deps != nil && deps.Kind == yaml.MappingNode {
+		required := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		schemas := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		for i := 0; i+1 < len(deps.Content); i += 2 {
+			if deps.Content[i+1].Kind == yaml.SequenceNode {
+				required.Content = append(required.Content, deps.Content[i], deps.Content[i+1])
+			} else {
+				schemas.Content = append(schemas.Content, deps.Content[i], deps.Content[i+1])
+			}
+		}
+		replacements := []*yaml.Node{}
+		if len(required.Content) > 0 {
+			replacements = append(replacements, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "dependentRequired"}, required)
+		}
+		if len(schemas.Content) > 0 {
+			replacements = append(replacements, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "dependentSchemas"}, schemas)
+		}
+		replaceKey(schema, "dependencies", replacements)
+	}
+}
+
+// downgradeSchema rewrites the 2020-12 keywords of one schema to their draft-07 form.
+func downgradeSchema(schema *yaml.Node, pointer string) error {
+	for _, keyword := range draft202012Only {
+		if mappingValue(schema, keyword) != nil {
+			return fmt.Errorf("at '#%s': %s can't be expressed in %s", pointer, keyword, Draft07)
+		}
+	}
+
+	renameKey(schema, "$defs", "definitions")
+
+	if mappingValue(schema, "prefixItems") != nil {
+		renameKey(schema, "items", "additionalItems")
+		renameKey(schema, "prefixItems", "items")
+	}
+
+	if anchor := mappingValue(schema, "$anchor"); anchor != nil {
+		if mappingValue(schema, "$id") != nil {
+			return fmt.Errorf("at '#%s': $anchor can't be combined with $id in %s", pointer, Draft07)
+		}
+		renameKey(schema, "$anchor", "$id")
+		anchor.Value = "#" + anchor.Value
+	}
+
+	required := mappingValue(schema, "dependentRequired")
+	schemas := mappingValue(schema, "dependentSchemas")
+	if required == nil && schemas == nil {
+		return nil
+	}
+	if mappingValue(schema, "dependencies") != nil {
+		return fmt.Errorf("at '#%s': dependencies can't be combined with dependentRequired or dependentSchemas", pointer)
+	}
+	deps := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, group := range []*yaml.Node{required, schemas} {
+		if group != nil {
+			deps.Content = append(deps.Content, group.Content...)
+		}
+	}
+	depsKey := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "dependencies"}
+	if required != nil {
+		replaceKey(schema, "dependentRequired", []*yaml.Node{depsKey, deps})
+		replaceKey(schema, "dependentSchemas", nil)
+	} else {
+		replaceKey(schema, "dependentSchemas", []*yaml.Node{depsKey, deps})
+	}
+	return nil
+}
+
+// rewriteRef points a schema's $ref into this document at the renamed definitions
+// keyword. Refs to other documents are left alone, since those aren't converted.
+func rewriteRef(schema *yaml.Node, upgrade bool) {
+	from, to := "#/definitions/", "#/$defs/"
+	if !upgrade {
+		from, to = to, from
+	}
+	if ref := mappingValue(schema, "$ref"); ref != nil && ref.Kind == yaml.ScalarNode {
+		if suffix, local := strings.CutPrefix(ref.Value, from); local {
+			ref.Value = to + suffix
+		}
+	}
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func renameKey(node *yaml.Node, from, to string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == from {
+			node.Content[i].Value = to
+			return
+		}
+	}
+}
+
+// replaceKey swaps the key/value pair for key with replacement, which may hold any
+// number of pairs, keeping its position in the mapping.
+func replaceKey(node *yaml.Node, key string, replacement []*yaml.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			content := append([]*yaml.Node{}, node.Content[:i]...)
+			content = append(content, replacement...)
+			node.Content = append(content, node.Content[i+2:]...)
+			return
+		}
+	}
+}
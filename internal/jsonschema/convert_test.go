@@ -0,0 +1,136 @@
+package jsonschema_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/massdriver-cloud/mass/internal/jsonschema"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		draft    jsonschema.Draft
+		wantPath string
+	}{
+		{
+			name:     "draft-07 to 2020-12",
+			input:    "testdata/convert/draft-07.json",
+			draft:    jsonschema.Draft202012,
+			wantPath: "testdata/convert/2020-12.json",
+		},
+		{
+			name:     "2020-12 to draft-07",
+			input:    "testdata/convert/2020-12.json",
+			draft:    jsonschema.Draft07,
+			wantPath: "testdata/convert/draft-07.json",
+		},
+		{
+			name:     "same draft is unchanged",
+			input:    "testdata/convert/draft-07.json",
+			draft:    jsonschema.Draft07,
+			wantPath: "testdata/convert/draft-07.json",
+		},
+		{
+			name:     "no draft only converts the format",
+			input:    "testdata/convert/2020-12.json",
+			wantPath: "testdata/convert/2020-12.json",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			data, readErr := os.ReadFile(tc.input)
+			if readErr != nil {
+				t.Fatal(readErr)
+			}
+			want, wantErr := os.ReadFile(tc.wantPath)
+			if wantErr != nil {
+				t.Fatal(wantErr)
+			}
+
+			got, err := jsonschema.Convert(data, ".json", ".json", tc.draft)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != string(want) {
+				t.Errorf("got:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestConvertAddsSchema(t *testing.T) {
+	got, err := jsonschema.Convert([]byte(`{"definitions": {"name": {"type": "string"}}}`), ".json", ".yaml", jsonschema.Draft202012)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `$schema: https://json-schema.org/draft/2020-12/schema
+$defs:
+  name:
+    type: string
+`
+	if string(got) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestConvertKeepsExternalRefs(t *testing.T) {
+	input := `{"properties": {"cidr": {"$ref": "#/definitions/cidr"}, "network": {"$ref": "./lib.json#/definitions/network"}}}`
+	got, err := jsonschema.Convert([]byte(input), ".json", ".yaml", jsonschema.Draft202012)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `$schema: https://json-schema.org/draft/2020-12/schema
+properties:
+  cidr:
+    $ref: '#/$defs/cidr'
+  network:
+    $ref: ./lib.json#/definitions/network
+`
+	if string(got) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestConvertErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		draft   jsonschema.Draft
+		wantErr string
+	}{
+		{
+			name:    "keyword draft-07 can't express",
+			input:   `{"$schema": "https://json-schema.org/draft/2020-12/schema", "properties": {"tags": {"type": "object", "unevaluatedProperties": false}}}`,
+			draft:   jsonschema.Draft07,
+			wantErr: "at '#/properties/tags': unevaluatedProperties can't be expressed in draft-07",
+		},
+		{
+			name:    "unsupported source draft",
+			input:   `{"$schema": "https://json-schema.org/draft/2019-09/schema"}`,
+			draft:   jsonschema.Draft202012,
+			wantErr: `unsupported $schema "https://json-schema.org/draft/2019-09/schema"`,
+		},
+		{
+			name:    "unsupported target draft",
+			input:   `{"type": "string"}`,
+			draft:   "draft-04",
+			wantErr: `unsupported draft "draft-04"`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := jsonschema.Convert([]byte(tc.input), ".json", ".json", tc.draft)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("got error %q, want it to contain %q", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// keywordOrder is the canonical order of schema keywords: identity and
+// annotations, then type-specific validation, then composition, then definitions.
+// Keywords that aren't listed follow in alphabetical order.
+var keywordOrder = []string{
+	"$schema", "$id", "$anchor", "$ref", "$dynamicRef", "$dynamicAnchor", "$comment",
+	"title", "description", "type", "format", "enum", "const", "default", "examples",
+	"readOnly", "writeOnly", "deprecated",
+	"minimum", "exclusiveMinimum", "maximum", "exclusiveMaximum", "multipleOf",
+	"minLength", "maxLength", "pattern", "contentEncoding", "contentMediaType", "contentSchema",
+	"items", "prefixItems", "additionalItems", "unevaluatedItems", "contains", "minContains", "maxContains", "minItems", "maxItems", "uniqueItems",
+	"required", "properties", "patternProperties", "additionalProperties", "unevaluatedProperties", "propertyNames", "minProperties", "maxProperties",
+	"dependencies", "dependentRequired", "dependentSchemas",
+	"allOf", "anyOf", "oneOf", "not", "if", "then", "else",
+	"definitions", "$defs",
+}
+
+// schemaMapKeywords hold a mapping of names to subschemas.
+var schemaMapKeywords = []string{"properties", "patternProperties", "definitions", "$defs", "dependentSchemas", "dependencies"}
+
+// schemaListKeywords hold a list of subschemas.
+var schemaListKeywords = []string{"allOf", "anyOf", "oneOf", "prefixItems", "items"}
+
+// schemaKeywords hold a single subschema.
+var schemaKeywords = []string{
+	"additionalProperties", "additionalItems", "items", "contains", "propertyNames", "not", "if", "then", "else",
+	"unevaluatedItems", "unevaluatedProperties", "contentSchema",
+}
+
+// Format reorders the keywords of a JSON or YAML schema document, and of every
+// subschema in it, into canonical order. Property names and definitions keep the
+// order they're written in. from and to are the file extensions of the document
+// and of the output, .json, .yaml or .yml; YAML comments are kept when both are YAML.
+func Format(data []byte, from, to string) ([]byte, error) {
+	root, parseErr := parseDocument(data)
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	if err := walkSchemas(root, "", func(schema *yaml.Node, _ string) error {
+		sortKeywords(schema)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return encodeDocument(root, from, to)
+}
+
+// parseDocument parses a JSON or YAML document into the node of its root value.
+func parseDocument(data []byte) (*yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, errors.New("schema must be an object")
+	}
+	return doc.Content[0], nil
+}
+
+// walkSchemas calls fn on schema and every subschema in it, parents first, with the
+// JSON pointer of each. Boolean subschemas are skipped.
+func walkSchemas(schema *yaml.Node, pointer string, fn func(schema *yaml.Node, pointer string) error) error {
+	if schema.Kind != yaml.MappingNode {
+		return nil
+	}
+	if err := fn(schema, pointer); err != nil {
+		return err
+	}
+	for i := 0; i+1 < len(schema.Content); i += 2 {
+		keyword := schema.Content[i].Value
+		value := schema.Content[i+1]
+		keywordPointer := pointer + "/" + escapePointerToken(keyword)
+		switch {
+		case slices.Contains(schemaMapKeywords, keyword) && value.Kind == yaml.MappingNode:
+			for j := 0; j+1 < len(value.Content); j += 2 {
+				if err := walkSchemas(value.Content[j+1], keywordPointer+"/"+escapePointerToken(value.Content[j].Value), fn); err != nil {
+					return err
+				}
+			}
+		case slices.Contains(schemaListKeywords, keyword) && value.Kind == yaml.SequenceNode:
+			for j, item := range value.Content {
+				if err := walkSchemas(item, fmt.Sprintf("%s/%d", keywordPointer, j), fn); err != nil {
+					return err
+				}
+			}
+		case slices.Contains(schemaKeywords, keyword):
+			if err := walkSchemas(value, keywordPointer, fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// sortKeywords reorders the keywords of a schema mapping node into canonical order.
+func sortKeywords(schema *yaml.Node) {
+	type pair struct{ key, value *yaml.Node }
+	pairs := make([]pair, 0, len(schema.Content)/2)
+	for i := 0; i+1 < len(schema.Content); i += 2 {
+		pairs = append(pairs, pair{schema.Content[i], schema.Content[i+1]})
+	}
+	slices.SortStableFunc(pairs, func(a, b pair) int {
+		rankA, rankB := keywordRank(a.key.Value), keywordRank(b.key.Value)
+		if rankA != rankB {
+			return rankA - rankB
+		}
+		return strings.Compare(a.key.Value, b.key.Value)
+	})
+	schema.Content = schema.Content[:0]
+	for _, p := range pairs {
+		schema.Content = append(schema.Content, p.key, p.value)
+	}
+}
+
+func keywordRank(keyword string) int {
+	if i := slices.Index(keywordOrder, keyword); i != -1 {
+		return i
+	}
+	return len(keywordOrder)
+}
+
+// encodeDocument writes the document rooted at root as JSON or YAML.
+func encodeDocument(root *yaml.Node, from, to string) ([]byte, error) {
+	switch to {
+	case ".json":
+		var buf bytes.Buffer
+		if err := encodeJSONNode(&buf, root, ""); err != nil {
+			return nil, err
+		}
+		buf.WriteByte('\n')
+		return buf.Bytes(), nil
+	case ".yaml", ".yml":
+		if from == ".json" {
+			// JSON parses as flow-style YAML with quoted strings
+			clearStyle(root)
+		}
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(root); err != nil {
+			return nil, err
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("unsupported file type: %s", to)
+	}
+}
+
+func clearStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		clearStyle(child)
+	}
+}
+
+// encodeJSONNode writes node as indented JSON, keeping the order of mapping keys.
+func encodeJSONNode(buf *bytes.Buffer, node *yaml.Node, indent string) error {
+	switch node.Kind {
+	case yaml.AliasNode:
+		return encodeJSONNode(buf, node.Alias, indent)
+	case yaml.MappingNode:
+		if len(node.Content) == 0 {
+			buf.WriteString("{}")
+			return nil
+		}
+		buf.WriteString("{\n")
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if i > 0 {
+				buf.WriteString(",\n")
+			}
+			buf.WriteString(indent + "  ")
+			if err := encodeJSONValue(buf, node.Content[i].Value); err != nil {
+				return err
+			}
+			buf.WriteString(": ")
+			if err := encodeJSONNode(buf, node.Content[i+1], indent+"  "); err != nil {
+				return err
+			}
+		}
+		buf.WriteString("\n" + indent + "}")
+	case yaml.SequenceNode:
+		if len(node.Content) == 0 {
+			buf.WriteString("[]")
+			return nil
+		}
+		buf.WriteString("[\n")
+		for i, item := range node.Content {
+			if i > 0 {
+				buf.WriteString(",\n")
+			}
+			buf.WriteString(indent + "  ")
+			if err := encodeJSONNode(buf, item, indent+"  "); err != nil {
+				return err
+			}
+		}
+		buf.WriteString("\n" + indent + "]")
+	default:
+		var value any
+		if err := node.Decode(&value); err != nil {
+			return err
+		}
+		return encodeJSONValue(buf, value)
+	}
+	return nil
+}
+
+func encodeJSONValue(buf *bytes.Buffer, value any) error {
+	var encoded bytes.Buffer
+	encoder := json.NewEncoder(&encoded)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return err
+	}
+	buf.Write(bytes.TrimSuffix(encoded.Bytes(), []byte("\n")))
+	return nil
+}
+
+func escapePointerToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
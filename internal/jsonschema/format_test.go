@@ -0,0 +1,108 @@
+package jsonschema_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/massdriver-cloud/mass/internal/jsonschema"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		from     string
+		to       string
+		wantPath string
+		want     string
+	}{
+		{
+			name:     "reorders JSON keywords and keeps property order",
+			input:    "testdata/format/unformatted.json",
+			from:     ".json",
+			to:       ".json",
+			wantPath: "testdata/format/formatted.json",
+		},
+		{
+			name:     "keeps YAML comments and styles",
+			input:    "testdata/format/unformatted.yaml",
+			from:     ".yaml",
+			to:       ".yaml",
+			wantPath: "testdata/format/formatted.yaml",
+		},
+		{
+			name:     "formatted JSON is unchanged",
+			input:    "testdata/format/formatted.json",
+			from:     ".json",
+			to:       ".json",
+			wantPath: "testdata/format/formatted.json",
+		},
+		{
+			name:  "JSON to YAML drops JSON quoting",
+			input: "testdata/format/unformatted.json",
+			from:  ".json",
+			to:    ".yaml",
+			want: `$schema: http://json-schema.org/draft-07/schema#
+title: Cluster
+type: object
+required:
+  - zone
+properties:
+  zone:
+    title: Zone
+    type: string
+    default: us-east-1a
+  count:
+    description: Number of <nodes> & replicas
+    type: integer
+    minimum: 1
+    $md.immutable: true
+`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			data, readErr := os.ReadFile(tc.input)
+			if readErr != nil {
+				t.Fatal(readErr)
+			}
+			want := tc.want
+			if tc.wantPath != "" {
+				wantData, wantErr := os.ReadFile(tc.wantPath)
+				if wantErr != nil {
+					t.Fatal(wantErr)
+				}
+				want = string(wantData)
+			}
+
+			got, err := jsonschema.Format(data, tc.from, tc.to)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestFormatErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		to    string
+	}{
+		{name: "not an object", input: `["type"]`, to: ".json"},
+		{name: "malformed", input: `{"type": `, to: ".json"},
+		{name: "unsupported output", input: `{"type": "string"}`, to: ".toml"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := jsonschema.Format([]byte(tc.input), ".json", tc.to); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
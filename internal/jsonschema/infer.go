@@ -0,0 +1,119 @@
+package jsonschema
+
+import (
+	"maps"
+	"math"
+	"slices"
+)
+
+// Infer generates a draft-07 schema that every example validates against. Examples
+// are decoded JSON values, as read by files.Read. Object properties are merged across
+// examples and required when every example of that object has them, array items are
+// merged into one schema, and a value seen with different types gets a type list.
+// Whole numbers are integers unless some example of the same value has a fraction.
+func Infer(examples ...any) map[string]any {
+	merged := &inferredSchema{}
+	for _, example := range examples {
+		merged.add(example)
+	}
+	schema := merged.schema()
+	schema["$schema"] = draftURIs[Draft07]
+	return schema
+}
+
+// inferredSchema accumulates the shape of every value seen at one place in the examples.
+type inferredSchema struct {
+	types      map[string]bool
+	objects    int
+	properties map[string]*inferredSchema
+	// present counts the objects each property appeared in
+	present map[string]int
+	items   *inferredSchema
+}
+
+func (s *inferredSchema) add(value any) {
+	if s.types == nil {
+		s.types = map[string]bool{}
+	}
+	switch typed := value.(type) {
+	case nil:
+		s.types["null"] = true
+	case bool:
+		s.types["boolean"] = true
+	case string:
+		s.types["string"] = true
+	case int, int32, int64:
+		s.types["integer"] = true
+	case float64:
+		if typed == math.Trunc(typed) && !math.IsInf(typed, 0) {
+			s.types["integer"] = true
+		} else {
+			s.types["number"] = true
+		}
+	case []any:
+		s.types["array"] = true
+		if s.items == nil {
+			s.items = &inferredSchema{}
+		}
+		for _, item := range typed {
+			s.items.add(item)
+		}
+	case map[string]any:
+		s.types["object"] = true
+		if s.properties == nil {
+			s.properties = map[string]*inferredSchema{}
+			s.present = map[string]int{}
+		}
+		s.objects++
+		for key, property := range typed {
+			if s.properties[key] == nil {
+				s.properties[key] = &inferredSchema{}
+			}
+			s.properties[key].add(property)
+			s.present[key]++
+		}
+	default:
+		// TOML dates and times, which JSON and YAML carry as strings
+		s.types["string"] = true
+	}
+}
+
+func (s *inferredSchema) schema() map[string]any {
+	schema := map[string]any{}
+	if s.types["number"] {
+		// every integer is also a number
+		delete(s.types, "integer")
+	}
+	types := slices.Sorted(maps.Keys(s.types))
+	switch len(types) {
+	case 0:
+		// an empty array's items, which any value validates against
+	case 1:
+		schema["type"] = types[0]
+	default:
+		typeList := make([]any, len(types))
+		for i, typeName := range types {
+			typeList[i] = typeName
+		}
+		schema["type"] = typeList
+	}
+
+	if s.items != nil && len(s.items.types) > 0 {
+		schema["items"] = s.items.schema()
+	}
+	if s.properties != nil {
+		properties := map[string]any{}
+		required := []any{}
+		for _, key := range slices.Sorted(maps.Keys(s.properties)) {
+			properties[key] = s.properties[key].schema()
+			if s.present[key] == s.objects {
+				required = append(required, key)
+			}
+		}
+		schema["properties"] = properties
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+	}
+	return schema
+}
@@ -0,0 +1,85 @@
+package jsonschema_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/massdriver-cloud/mass/internal/jsonschema"
+)
+
+func TestInfer(t *testing.T) {
+	tests := []struct {
+		name     string
+		examples []any
+		want     map[string]any
+	}{
+		{
+			name:     "scalar",
+			examples: []any{"us-east-1"},
+			want:     map[string]any{"type": "string"},
+		},
+		{
+			name: "object properties",
+			examples: []any{
+				map[string]any{"name": "db", "port": float64(5432), "public": false},
+			},
+			want: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"name":   map[string]any{"type": "string"},
+					"port":   map[string]any{"type": "integer"},
+					"public": map[string]any{"type": "boolean"},
+				},
+				"required": []any{"name", "port", "public"},
+			},
+		},
+		{
+			name: "merges examples",
+			examples: []any{
+				map[string]any{"name": "db", "ratio": float64(1), "note": nil},
+				map[string]any{"name": "cache", "ratio": 0.5, "note": "primary", "tags": []any{}},
+			},
+			want: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"name":  map[string]any{"type": "string"},
+					"note":  map[string]any{"type": []any{"null", "string"}},
+					"ratio": map[string]any{"type": "number"},
+					"tags":  map[string]any{"type": "array"},
+				},
+				"required": []any{"name", "note", "ratio"},
+			},
+		},
+		{
+			name: "merges array items",
+			examples: []any{
+				[]any{
+					map[string]any{"cidr": "10.0.0.0/24", "zone": "a"},
+					map[string]any{"cidr": "10.0.1.0/24"},
+				},
+			},
+			want: map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"cidr": map[string]any{"type": "string"},
+						"zone": map[string]any{"type": "string"},
+					},
+					"required": []any{"cidr"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.want["$schema"] = "http://json-schema.org/draft-07/schema#"
+
+			got := jsonschema.Infer(tc.examples...)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -1,4 +1,4 @@
-// Package jsonschema provides utilities for loading and validating JSON schemas.
+// Package jsonschema provides utilities for loading, validating, inferring, converting and formatting JSON schemas.
 package jsonschema
 
 import (
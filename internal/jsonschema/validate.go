@@ -3,6 +3,7 @@ package jsonschema
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/santhosh-tekuri/jsonschema/v6"
@@ -41,3 +42,26 @@ func ValidateGo(sch *jsonschema.Schema, document any) error {
 
 	return sch.Validate(doc)
 }
+
+// ValidationMessages flattens a validation error into one message per failed keyword,
+// each prefixed with the JSON pointer of the offending location in the document.
+// Errors that didn't come from validation are returned as their single message.
+func ValidationMessages(err error) []string {
+	var validationErr *jsonschema.ValidationError
+	if !errors.As(err, &validationErr) {
+		return []string{err.Error()}
+	}
+
+	messages := []string{}
+	for _, unit := range validationErr.BasicOutput().Errors {
+		if unit.Error == nil {
+			continue
+		}
+		location := unit.InstanceLocation
+		if location == "" {
+			location = "/"
+		}
+		messages = append(messages, fmt.Sprintf("at '%s': %s", location, unit.Error))
+	}
+	return messages
+}
@@ -1,6 +1,9 @@
 package jsonschema_test
 
 import (
+	"errors"
+	"reflect"
+	"slices"
 	"testing"
 
 	"github.com/massdriver-cloud/mass/internal/jsonschema"
@@ -181,3 +184,26 @@ func TestValidateGo(t *testing.T) {
 		})
 	}
 }
+
+func TestValidationMessages(t *testing.T) {
+	schema, err := jsonschema.LoadSchemaFromFile("testdata/valid-schema.json")
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+
+	err = jsonschema.ValidateGo(schema, map[string]any{"checked": "should be boolean"})
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+
+	got := jsonschema.ValidationMessages(err)
+	want := "at '/checked': got string, want boolean"
+	if !slices.Contains(got, want) {
+		t.Errorf("ValidationMessages() = %v, want it to contain %q", got, want)
+	}
+
+	other := jsonschema.ValidationMessages(errors.New("boom"))
+	if !reflect.DeepEqual(other, []string{"boom"}) {
+		t.Errorf("ValidationMessages() = %v, want [boom]", other)
+	}
+}
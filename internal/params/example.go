@@ -0,0 +1,233 @@
+package params
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// ExampleOptions controls Example.
+type ExampleOptions struct {
+	// RequiredOnly leaves out every property the schema doesn't require.
+	RequiredOnly bool
+}
+
+// examplePlaceholder is used for strings with no default, example, enum or format.
+const examplePlaceholder = "example"
+
+// formatExamples are placeholders for the string formats Massdriver schemas commonly use.
+var formatExamples = map[string]string{
+	"date-time": "2024-01-01T00:00:00Z",
+	"date":      "2024-01-01",
+	"time":      "00:00:00Z",
+	"email":     "user@example.com",
+	"hostname":  "example.com",
+	"ipv4":      "192.0.2.1",
+	"ipv6":      "2001:db8::1",
+	"uri":       "https://example.com",
+	"url":       "https://example.com",
+	"uuid":      "00000000-0000-0000-0000-000000000000",
+}
+
+// Example builds an example document for a dereferenced params schema. Each value is
+// the schema's default, const, first example or first enum value, in that order of
+// preference, falling back to a placeholder that fits its type and constraints.
+func Example(sch map[string]any, opts ExampleOptions) any {
+	sch = mergeAllOf(sch)
+
+	if value, ok := sch["default"]; ok {
+		return value
+	}
+	if value, ok := sch["const"]; ok {
+		return value
+	}
+	if examples, ok := sch["examples"].([]any); ok && len(examples) > 0 {
+		return examples[0]
+	}
+	if enum, ok := sch["enum"].([]any); ok && len(enum) > 0 {
+		return enum[0]
+	}
+
+	switch exampleType(sch) {
+	case "object":
+		return exampleObject(sch, opts)
+	case "array":
+		return exampleArray(sch, opts)
+	case "string":
+		return exampleString(sch)
+	case "integer":
+		return int64(exampleNumber(sch, true))
+	case "number":
+		return exampleNumber(sch, false)
+	case "boolean":
+		return false
+	}
+
+	for _, keyword := range []string{"oneOf", "anyOf"} {
+		if options, ok := sch[keyword].([]any); ok && len(options) > 0 {
+			if first, isMap := options[0].(map[string]any); isMap {
+				return Example(first, opts)
+			}
+		}
+	}
+	return nil
+}
+
+func exampleType(sch map[string]any) string {
+	switch schemaType := sch["type"].(type) {
+	case string:
+		return schemaType
+	case []any:
+		for _, t := range schemaType {
+			if t != "null" {
+				typeStr, _ := t.(string)
+				return typeStr
+			}
+		}
+		return "null"
+	}
+	if _, ok := sch["properties"]; ok {
+		return "object"
+	}
+	if _, ok := sch["items"]; ok {
+		return "array"
+	}
+	return ""
+}
+
+func exampleObject(sch map[string]any, opts ExampleOptions) map[string]any {
+	props, _ := sch["properties"].(map[string]any)
+	required, _ := sch["required"].([]any)
+
+	example := map[string]any{}
+	for name, prop := range props {
+		if opts.RequiredOnly && !slices.Contains(required, any(name)) {
+			continue
+		}
+		propSchema, _ := prop.(map[string]any)
+		example[name] = Example(propSchema, opts)
+	}
+
+	// a oneOf/anyOf beside properties usually adds conditional properties; fill in the
+	// first option's so the object satisfies it
+	for _, keyword := range []string{"oneOf", "anyOf"} {
+		options, _ := sch[keyword].([]any)
+		if len(options) == 0 {
+			continue
+		}
+		first, _ := options[0].(map[string]any)
+		if nested, isObject := Example(first, opts).(map[string]any); isObject {
+			for name, value := range nested {
+				if _, exists := example[name]; !exists {
+					example[name] = value
+				}
+			}
+		}
+	}
+	return example
+}
+
+func exampleArray(sch map[string]any, opts ExampleOptions) []any {
+	count := 1
+	if opts.RequiredOnly {
+		count = 0
+	}
+	if minItems, ok := sch["minItems"].(float64); ok {
+		count = max(count, int(minItems))
+	}
+	if maxItems, ok := sch["maxItems"].(float64); ok {
+		count = min(count, int(maxItems))
+	}
+
+	items, _ := sch["items"].(map[string]any)
+	example := make([]any, 0, count)
+	for i := range count {
+		item := Example(items, opts)
+		// keep string items distinct, in case the array requires uniqueItems
+		if str, isString := item.(string); isString && i > 0 {
+			if enum, hasEnum := items["enum"].([]any); hasEnum && i < len(enum) {
+				item = enum[i]
+			} else {
+				item = fmt.Sprintf("%s-%d", str, i+1)
+			}
+		}
+		example = append(example, item)
+	}
+	return example
+}
+
+func exampleString(sch map[string]any) string {
+	example := examplePlaceholder
+	if format, ok := sch["format"].(string); ok {
+		if formatted, known := formatExamples[format]; known {
+			example = formatted
+		}
+	}
+	if minLength, ok := sch["minLength"].(float64); ok && len(example) < int(minLength) {
+		example += strings.Repeat("x", int(minLength)-len(example))
+	}
+	if maxLength, ok := sch["maxLength"].(float64); ok && len(example) > int(maxLength) {
+		example = example[:int(maxLength)]
+	}
+	return example
+}
+
+func exampleNumber(sch map[string]any, integer bool) float64 {
+	value := 0.0
+	if minimum, ok := sch["minimum"].(float64); ok {
+		value = minimum
+	}
+	if exclusiveMinimum, ok := sch["exclusiveMinimum"].(float64); ok && value <= exclusiveMinimum {
+		value = exclusiveMinimum + 1
+		if !integer {
+			value = exclusiveMinimum + 0.5
+		}
+	}
+	if maximum, ok := sch["maximum"].(float64); ok && value > maximum {
+		value = maximum
+	}
+	if multipleOf, ok := sch["multipleOf"].(float64); ok && multipleOf > 0 {
+		value = math.Ceil(value/multipleOf) * multipleOf
+	}
+	if integer {
+		value = math.Ceil(value)
+	}
+	return value
+}
+
+// mergeAllOf folds the keywords of every allOf subschema into a copy of sch. Keywords
+// already set on sch win; properties and required names are combined.
+func mergeAllOf(sch map[string]any) map[string]any {
+	allOf, ok := sch["allOf"].([]any)
+	if !ok {
+		return sch
+	}
+
+	merged := map[string]any{}
+	for key, value := range sch {
+		if key != "allOf" {
+			merged[key] = value
+		}
+	}
+	for _, sub := range allOf {
+		subSchema, isMap := sub.(map[string]any)
+		if !isMap {
+			continue
+		}
+		subSchema = mergeAllOf(subSchema)
+		combined := MergeSchemas(subSchema, merged)
+		for key, value := range subSchema {
+			if _, exists := merged[key]; !exists {
+				merged[key] = value
+			}
+		}
+		if props, _ := combined["properties"].(map[string]any); len(props) > 0 {
+			merged["properties"] = props
+		}
+		if required, _ := combined["required"].([]any); len(required) > 0 {
+			merged["required"] = required
+		}
+	}
+	return merged
+}
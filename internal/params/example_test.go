@@ -0,0 +1,182 @@
+package params_test
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/massdriver-cloud/mass/internal/files"
+	"github.com/massdriver-cloud/mass/internal/jsonschema"
+	"github.com/massdriver-cloud/mass/internal/params"
+)
+
+func TestExample(t *testing.T) {
+	type test struct {
+		name string
+		sch  map[string]any
+		opts params.ExampleOptions
+		want any
+	}
+	tests := []test{
+		{
+			name: "prefers defaults, const, examples and enums",
+			sch: map[string]any{
+				"type":     "object",
+				"required": []any{"region", "size", "tier", "name", "mode"},
+				"properties": map[string]any{
+					"region": map[string]any{"type": "string", "default": "us-west-2"},
+					"size":   map[string]any{"type": "integer", "const": float64(3)},
+					"tier":   map[string]any{"type": "string", "examples": []any{"premium"}, "enum": []any{"basic", "premium"}},
+					"name":   map[string]any{"type": "string", "enum": []any{"primary", "replica"}},
+					"mode":   map[string]any{"type": "boolean"},
+				},
+			},
+			want: map[string]any{
+				"region": "us-west-2",
+				"size":   float64(3),
+				"tier":   "premium",
+				"name":   "primary",
+				"mode":   false,
+			},
+		},
+		{
+			name: "placeholders fit constraints",
+			sch: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"email":    map[string]any{"type": "string", "format": "email"},
+					"code":     map[string]any{"type": "string", "minLength": float64(10)},
+					"short":    map[string]any{"type": "string", "maxLength": float64(3)},
+					"replicas": map[string]any{"type": "integer", "minimum": float64(2), "maximum": float64(5)},
+					"port":     map[string]any{"type": "integer", "exclusiveMinimum": float64(1024)},
+					"ratio":    map[string]any{"type": "number", "minimum": float64(0.3), "multipleOf": float64(0.25)},
+					"tags":     map[string]any{"type": "array", "minItems": float64(2), "uniqueItems": true, "items": map[string]any{"type": "string"}},
+					"zones":    map[string]any{"type": "array", "items": map[string]any{"type": "string", "enum": []any{"a", "b"}}},
+					"optional": map[string]any{"type": []any{"null", "string"}},
+				},
+			},
+			want: map[string]any{
+				"email":    "user@example.com",
+				"code":     "examplexxx",
+				"short":    "exa",
+				"replicas": int64(2),
+				"port":     int64(1025),
+				"ratio":    0.5,
+				"tags":     []any{"example", "example-2"},
+				"zones":    []any{"a"},
+				"optional": "example",
+			},
+		},
+		{
+			name: "required only",
+			sch: map[string]any{
+				"type":     "object",
+				"required": []any{"database"},
+				"properties": map[string]any{
+					"database": map[string]any{
+						"type":     "object",
+						"required": []any{"engine"},
+						"properties": map[string]any{
+							"engine":  map[string]any{"type": "string", "enum": []any{"postgres", "mysql"}},
+							"version": map[string]any{"type": "string", "default": "16"},
+						},
+					},
+					"backups": map[string]any{"type": "boolean", "default": true},
+				},
+			},
+			opts: params.ExampleOptions{RequiredOnly: true},
+			want: map[string]any{
+				"database": map[string]any{"engine": "postgres"},
+			},
+		},
+		{
+			name: "allOf and oneOf",
+			sch: map[string]any{
+				"allOf": []any{
+					map[string]any{
+						"type":       "object",
+						"required":   []any{"name"},
+						"properties": map[string]any{"name": map[string]any{"type": "string"}},
+					},
+					map[string]any{
+						"properties": map[string]any{"size": map[string]any{"type": "integer", "minimum": float64(1)}},
+					},
+				},
+				"oneOf": []any{
+					map[string]any{
+						"required":   []any{"storage"},
+						"properties": map[string]any{"storage": map[string]any{"type": "string", "const": "ssd"}},
+					},
+					map[string]any{
+						"required":   []any{"iops"},
+						"properties": map[string]any{"iops": map[string]any{"type": "integer"}},
+					},
+				},
+			},
+			want: map[string]any{
+				"name":    "example",
+				"size":    int64(1),
+				"storage": "ssd",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := params.Example(tc.sch, tc.opts)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("got %#v, want %#v", got, tc.want)
+			}
+
+			sch, err := jsonschema.LoadSchemaFromGo(tc.sch)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			data, err := json.Marshal(got)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if err = jsonschema.ValidateBytes(sch, data); err != nil {
+				t.Fatalf("example doesn't validate against its schema: %s", err)
+			}
+		})
+	}
+}
+
+func TestExampleRoundTrip(t *testing.T) {
+	sch := map[string]any{
+		"type":     "object",
+		"required": []any{"cidr", "replicas", "tags", "database"},
+		"properties": map[string]any{
+			"cidr":     map[string]any{"type": "string", "default": "10.0.0.0/16"},
+			"replicas": map[string]any{"type": "integer", "minimum": float64(1)},
+			"tags":     map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+			"database": map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"enabled": map[string]any{"type": "boolean", "default": true}},
+			},
+		},
+	}
+	compiled, err := jsonschema.LoadSchemaFromGo(sch)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	example := params.Example(sch, params.ExampleOptions{})
+
+	for _, ext := range []string{".json", ".yaml", ".tfvars"} {
+		t.Run(ext, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "params"+ext)
+			if err := files.Write(path, example); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			got := map[string]any{}
+			if err := files.Read(path, &got); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if err := jsonschema.ValidateGo(compiled, got); err != nil {
+				t.Fatalf("example read back from %s doesn't validate: %s", ext, err)
+			}
+		})
+	}
+}
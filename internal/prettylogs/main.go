@@ -1,7 +1,11 @@
 // Package prettylogs provides styled terminal output helpers using lipgloss.
 package prettylogs
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
 
 // Underline returns a lipgloss style with underline formatting applied to word.
 func Underline(word string) lipgloss.Style {
@@ -22,3 +26,27 @@ func Orange(word string) lipgloss.Style {
 func Red(word string) lipgloss.Style {
 	return lipgloss.NewStyle().SetString(word).Foreground(lipgloss.Color("#FF0000"))
 }
+
+// Diff colors the added and removed lines of a unified diff.
+func Diff(diff string) string {
+	var sb strings.Builder
+	for _, line := range strings.SplitAfter(diff, "\n") {
+		text := strings.TrimSuffix(line, "\n")
+		switch {
+		case strings.HasPrefix(text, "+++"), strings.HasPrefix(text, "---"):
+			sb.WriteString(line)
+			continue
+		case strings.HasPrefix(text, "+"):
+			sb.WriteString(Green(text).String())
+		case strings.HasPrefix(text, "-"):
+			sb.WriteString(Red(text).String())
+		default:
+			sb.WriteString(line)
+			continue
+		}
+		if strings.HasSuffix(line, "\n") {
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
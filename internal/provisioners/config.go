@@ -0,0 +1,121 @@
+package provisioners
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/massdriver-cloud/mass/internal/jsonschema"
+)
+
+// The config schemas list the keys these provisioners are known to read. The platform
+// may read others, so values of listed keys are validated strictly while unlisted keys
+// are only reported by UnknownConfigKeys, to catch typos without rejecting bundles.
+//
+//go:embed schemas/*.json
+var configSchemas embed.FS
+
+// ConfigValidator is implemented by provisioners that define a schema for their step config.
+type ConfigValidator interface {
+	// ValidateConfig returns an error when a config value doesn't match the schema.
+	ValidateConfig(config map[string]any) error
+	// UnknownConfigKeys returns the config keys the schema doesn't declare.
+	UnknownConfigKeys(config map[string]any) ([]UnknownConfigKey, error)
+}
+
+// UnknownConfigKey is a step config key a provisioner's config schema doesn't declare.
+// Path is dot-separated for nested keys, and Known lists the keys declared alongside it.
+type UnknownConfigKey struct {
+	Path  string
+	Known []string
+}
+
+// ValidateConfig validates a step config against the OpenTofu provisioner config schema.
+func (p *OpentofuProvisioner) ValidateConfig(config map[string]any) error {
+	return validateConfig("opentofu-config.json", config)
+}
+
+// ValidateConfig validates a step config against the Helm provisioner config schema.
+func (p *HelmProvisioner) ValidateConfig(config map[string]any) error {
+	return validateConfig("helm-config.json", config)
+}
+
+// ValidateConfig validates a step config against the Bicep provisioner config schema.
+func (p *BicepProvisioner) ValidateConfig(config map[string]any) error {
+	return validateConfig("bicep-config.json", config)
+}
+
+// UnknownConfigKeys returns the step config keys the OpenTofu provisioner config schema doesn't declare.
+func (p *OpentofuProvisioner) UnknownConfigKeys(config map[string]any) ([]UnknownConfigKey, error) {
+	return unknownConfigKeys("opentofu-config.json", config)
+}
+
+// UnknownConfigKeys returns the step config keys the Helm provisioner config schema doesn't declare.
+func (p *HelmProvisioner) UnknownConfigKeys(config map[string]any) ([]UnknownConfigKey, error) {
+	return unknownConfigKeys("helm-config.json", config)
+}
+
+// UnknownConfigKeys returns the step config keys the Bicep provisioner config schema doesn't declare.
+func (p *BicepProvisioner) UnknownConfigKeys(config map[string]any) ([]UnknownConfigKey, error) {
+	return unknownConfigKeys("bicep-config.json", config)
+}
+
+func unknownConfigKeys(schemaFile string, config map[string]any) ([]UnknownConfigKey, error) {
+	schemaBytes, readErr := configSchemas.ReadFile("schemas/" + schemaFile)
+	if readErr != nil {
+		return nil, readErr
+	}
+	var sch map[string]any
+	if err := json.Unmarshal(schemaBytes, &sch); err != nil {
+		return nil, fmt.Errorf("failed to parse provisioner config schema: %w", err)
+	}
+
+	unknown := []UnknownConfigKey{}
+	collectUnknownKeys("", sch, config, &unknown)
+	return unknown, nil
+}
+
+// collectUnknownKeys appends the keys of value that sch doesn't declare, descending into
+// declared keys whose schemas declare properties of their own.
+func collectUnknownKeys(prefix string, sch map[string]any, value map[string]any, unknown *[]UnknownConfigKey) {
+	props, ok := sch["properties"].(map[string]any)
+	if !ok {
+		return
+	}
+	known := slices.Sorted(maps.Keys(props))
+	for _, key := range slices.Sorted(maps.Keys(value)) {
+		propSchema, declared := props[key].(map[string]any)
+		if !declared {
+			*unknown = append(*unknown, UnknownConfigKey{Path: prefix + key, Known: known})
+			continue
+		}
+		if nested, isObject := value[key].(map[string]any); isObject {
+			collectUnknownKeys(prefix+key+".", propSchema, nested, unknown)
+		}
+	}
+}
+
+func validateConfig(schemaFile string, config map[string]any) error {
+	schemaBytes, readErr := configSchemas.ReadFile("schemas/" + schemaFile)
+	if readErr != nil {
+		return readErr
+	}
+
+	sch, loadErr := jsonschema.LoadSchemaFromReader(bytes.NewReader(schemaBytes))
+	if loadErr != nil {
+		return fmt.Errorf("failed to compile provisioner config schema: %w", loadErr)
+	}
+
+	if config == nil {
+		config = map[string]any{}
+	}
+	if err := jsonschema.ValidateGo(sch, config); err != nil {
+		return errors.New(strings.Join(jsonschema.ValidationMessages(err), "; "))
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package provisioners_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/massdriver-cloud/mass/internal/provisioners"
+)
+
+func TestValidateConfig(t *testing.T) {
+	type test struct {
+		name        string
+		provisioner string
+		config      map[string]any
+		errString   string
+	}
+	tests := []test{
+		{
+			name:        "empty opentofu config",
+			provisioner: "opentofu",
+		},
+		{
+			name:        "valid opentofu config",
+			provisioner: "terraform",
+			config:      map[string]any{"checkov": map[string]any{"enable": true, "halt_on_failure": false}},
+		},
+		{
+			name:        "unknown opentofu checkov option",
+			provisioner: "opentofu",
+			config:      map[string]any{"checkov": map[string]any{"halt_on_fail": true}},
+		},
+		{
+			name:        "wrong opentofu checkov type",
+			provisioner: "opentofu",
+			config:      map[string]any{"checkov": map[string]any{"enable": "yes"}},
+			errString:   "enable",
+		},
+		{
+			name:        "valid helm config",
+			provisioner: "helm",
+			config:      map[string]any{"namespace": "default", "release_name": "app"},
+		},
+		{
+			name:        "wrong bicep type",
+			provisioner: "bicep",
+			config:      map[string]any{"complete": "yes"},
+			errString:   "complete",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			validator, ok := provisioners.NewProvisioner(tc.provisioner).(provisioners.ConfigValidator)
+			if !ok {
+				t.Fatalf("provisioner %s doesn't validate config", tc.provisioner)
+			}
+
+			err := validator.ValidateConfig(tc.config)
+			if tc.errString == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tc.errString != "" && (err == nil || !strings.Contains(err.Error(), tc.errString)) {
+				t.Fatalf("got error %v want %s", err, tc.errString)
+			}
+		})
+	}
+}
+
+func TestUnknownConfigKeys(t *testing.T) {
+	type test struct {
+		name        string
+		provisioner string
+		config      map[string]any
+		want        []provisioners.UnknownConfigKey
+	}
+	tests := []test{
+		{
+			name:        "known keys",
+			provisioner: "helm",
+			config:      map[string]any{"namespace": "default", "checkov": map[string]any{"enable": true}},
+			want:        []provisioners.UnknownConfigKey{},
+		},
+		{
+			name:        "unknown top-level and nested keys",
+			provisioner: "opentofu",
+			config:      map[string]any{"jsn": true, "checkov": map[string]any{"halt_on_fail": true}},
+			want: []provisioners.UnknownConfigKey{
+				{Path: "checkov.halt_on_fail", Known: []string{"enable", "halt_on_failure", "quiet"}},
+				{Path: "jsn", Known: []string{"checkov", "json"}},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			validator, ok := provisioners.NewProvisioner(tc.provisioner).(provisioners.ConfigValidator)
+			if !ok {
+				t.Fatalf("provisioner %s doesn't validate config", tc.provisioner)
+			}
+
+			got, err := validator.UnknownConfigKeys(tc.config)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
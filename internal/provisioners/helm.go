@@ -3,10 +3,12 @@ package provisioners
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 
 	"github.com/massdriver-cloud/airlock/pkg/helm"
+	yaml3 "gopkg.in/yaml.v3"
 )
 
 // HelmProvisioner implements Provisioner for Helm charts.
@@ -18,9 +20,11 @@ func (p *HelmProvisioner) ExportMassdriverInputs(_ string, _ map[string]any) err
 	return nil
 }
 
-// ReadProvisionerInputs reads the Helm values.yaml and returns its schema as a map.
+// ReadProvisionerInputs reads the Helm values.yaml and returns its schema as a map. Descriptions,
+// type hints and @schema annotations are taken from the helm-docs style comments above each key.
 func (p *HelmProvisioner) ReadProvisionerInputs(stepPath string) (map[string]any, error) {
-	helmParamsImport := helm.HelmToSchema(filepath.Join(stepPath, "values.yaml"))
+	valuesPath := filepath.Join(stepPath, "values.yaml")
+	helmParamsImport := helm.HelmToSchema(valuesPath)
 
 	schemaBytes, marshallErr := json.Marshal(helmParamsImport.Schema)
 	if marshallErr != nil {
@@ -33,9 +37,47 @@ func (p *HelmProvisioner) ReadProvisionerInputs(stepPath string) (map[string]any
 		return nil, unmarshalErr
 	}
 
+	valuesNode, readErr := readHelmValuesNode(valuesPath)
+	if readErr != nil {
+		return nil, readErr
+	}
+	if annotateErr := annotateHelmSchema(variables, valuesNode); annotateErr != nil {
+		return nil, fmt.Errorf("failed to read comments in %s: %w", valuesPath, annotateErr)
+	}
+
 	return variables, nil
 }
 
+// ReadProvisionerUI returns a UI schema ordering every field the way it appears in values.yaml.
+func (p *HelmProvisioner) ReadProvisionerUI(stepPath string) (map[string]any, error) {
+	valuesNode, err := readHelmValuesNode(filepath.Join(stepPath, "values.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	return helmUIOrder(valuesNode), nil
+}
+
+// readHelmValuesNode returns the top-level mapping of a values.yaml file, or nil if the file
+// is missing or empty.
+func readHelmValuesNode(valuesPath string) (*yaml3.Node, error) {
+	valuesBytes, readErr := os.ReadFile(valuesPath)
+	if errors.Is(readErr, os.ErrNotExist) {
+		return nil, nil //nolint:nilnil // a missing values.yaml simply has nothing to annotate
+	}
+	if readErr != nil {
+		return nil, readErr
+	}
+
+	var document yaml3.Node
+	if err := yaml3.Unmarshal(valuesBytes, &document); err != nil {
+		return nil, err
+	}
+	if len(document.Content) == 0 {
+		return nil, nil //nolint:nilnil // an empty values.yaml simply has nothing to annotate
+	}
+	return document.Content[0], nil
+}
+
 // InitializeStep copies the Helm chart directory into the step directory.
 func (p *HelmProvisioner) InitializeStep(stepPath string, sourcePath string) error {
 	pathInfo, statErr := os.Stat(sourcePath)
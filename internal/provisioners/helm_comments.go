@@ -0,0 +1,166 @@
+package provisioners
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	yaml3 "gopkg.in/yaml.v3"
+)
+
+// helmDocsTypeHint matches the optional type hint helm-docs allows at the start of a
+// description, e.g. "# -- (int) Number of replicas".
+var helmDocsTypeHint = regexp.MustCompile(`^\(([a-zA-Z/]+)\)\s*`)
+
+var helmDocsTypes = map[string]string{
+	"string": "string",
+	"int":    "integer",
+	"float":  "number",
+	"bool":   "boolean",
+	"list":   "array",
+	"object": "object",
+	"dict":   "object",
+}
+
+// helmValueComment is the documentation parsed from the comment above a values.yaml key.
+type helmValueComment struct {
+	description string
+	typeHint    string
+	annotations map[string]any
+}
+
+// parseHelmValueComment parses a values.yaml head comment. helm-docs descriptions start
+// with "# --" and continue over the following comment lines; "# @schema" blocks hold YAML
+// JSON Schema keywords. Plain comments are only used as a description when there is no
+// helm-docs description, since they are often commented-out values.
+func parseHelmValueComment(comment string) (helmValueComment, error) {
+	parsed := helmValueComment{}
+	docLines, plainLines, schemaLines := []string{}, []string{}, []string{}
+	inDoc, inSchema := false, false
+
+	for line := range strings.SplitSeq(comment, "\n") {
+		line = strings.TrimSpace(line)
+		line = strings.TrimPrefix(strings.TrimPrefix(line, "#"), " ")
+
+		switch {
+		case strings.TrimSpace(line) == "@schema":
+			inSchema = !inSchema
+			inDoc = false
+		case inSchema:
+			schemaLines = append(schemaLines, line)
+		case line == "--" || strings.HasPrefix(line, "-- "):
+			inDoc = true
+			docLines = append(docLines, strings.TrimSpace(strings.TrimPrefix(line, "--")))
+		case strings.HasPrefix(line, "@"):
+			// other helm-docs annotations (@default, @section, @raw) aren't schema
+			inDoc = false
+		case strings.TrimSpace(line) == "":
+			inDoc = false
+		case inDoc:
+			docLines = append(docLines, strings.TrimSpace(line))
+		default:
+			plainLines = append(plainLines, strings.TrimSpace(line))
+		}
+	}
+
+	if len(schemaLines) > 0 {
+		if err := yaml3.Unmarshal([]byte(strings.Join(schemaLines, "\n")), &parsed.annotations); err != nil {
+			return parsed, fmt.Errorf("failed to parse @schema annotation: %w", err)
+		}
+	}
+
+	description := strings.Join(plainLines, " ")
+	if len(docLines) > 0 {
+		description = strings.Join(docLines, " ")
+		if match := helmDocsTypeHint.FindStringSubmatch(description); match != nil {
+			parsed.typeHint = helmDocsTypes[strings.ToLower(match[1])]
+			description = description[len(match[0]):]
+		}
+	}
+	parsed.description = strings.TrimSpace(description)
+
+	return parsed, nil
+}
+
+// annotateHelmSchema walks a values.yaml mapping node alongside the schema airlock
+// generated from it, applying the descriptions, type hints and @schema annotations
+// found in each key's comment.
+func annotateHelmSchema(sch map[string]any, node *yaml3.Node) error {
+	if node == nil || node.Kind != yaml3.MappingNode {
+		return nil
+	}
+	props, ok := sch["properties"].(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, valueNode := node.Content[i], node.Content[i+1]
+		prop, propOk := props[keyNode.Value].(map[string]any)
+		if !propOk {
+			continue
+		}
+
+		comment, err := parseHelmValueComment(keyNode.HeadComment)
+		if err != nil {
+			return fmt.Errorf("%s: %w", keyNode.Value, err)
+		}
+
+		delete(prop, "description")
+		if comment.description != "" {
+			prop["description"] = comment.description
+		}
+		if comment.typeHint != "" && prop["type"] == nil {
+			prop["type"] = comment.typeHint
+			delete(prop, "$comment")
+		}
+		for keyword, value := range comment.annotations {
+			if keyword == "required" {
+				if required, isBool := value.(bool); isBool {
+					setRequired(sch, keyNode.Value, required)
+					continue
+				}
+			}
+			prop[keyword] = value
+			if keyword == "type" {
+				delete(prop, "$comment")
+			}
+		}
+
+		if err = annotateHelmSchema(prop, valueNode); err != nil {
+			return fmt.Errorf("%s.%w", keyNode.Value, err)
+		}
+	}
+
+	return nil
+}
+
+func setRequired(sch map[string]any, name string, required bool) {
+	current, _ := sch["required"].([]any)
+	current = slices.DeleteFunc(slices.Clone(current), func(r any) bool { return r == name })
+	if required {
+		current = append(current, name)
+	}
+	sch["required"] = current
+}
+
+// helmUIOrder returns an RJSF UI schema that lists every mapping's keys in the order
+// they appear in values.yaml, recursing into nested mappings.
+func helmUIOrder(node *yaml3.Node) map[string]any {
+	if node == nil || node.Kind != yaml3.MappingNode {
+		return nil
+	}
+
+	ui := map[string]any{}
+	order := []any{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i].Value
+		order = append(order, key)
+		if nested := helmUIOrder(node.Content[i+1]); nested != nil {
+			ui[key] = nested
+		}
+	}
+	ui["ui:order"] = order
+	return ui
+}
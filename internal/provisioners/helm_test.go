@@ -36,6 +36,52 @@ func TestHelmReadProvisionerInputs(t *testing.T) {
 				"type": "object",
 			},
 		},
+		{
+			name: "documented",
+			want: map[string]any{
+				"required": []any{"replicaCount", "image"},
+				"properties": map[string]any{
+					"replicaCount": map[string]any{
+						"title":       "replicaCount",
+						"type":        "integer",
+						"default":     float64(1),
+						"description": "Number of replicas to run",
+					},
+					"image": map[string]any{
+						"title":    "image",
+						"type":     "object",
+						"required": []any{"repository", "tag", "pullPolicy"},
+						"properties": map[string]any{
+							"repository": map[string]any{
+								"title":       "repository",
+								"type":        "string",
+								"default":     "nginx",
+								"description": "Image repository pulled from the registry below",
+							},
+							"tag": map[string]any{
+								"title":       "tag",
+								"type":        "string",
+								"description": "Image tag, defaults to the chart appVersion",
+							},
+							"pullPolicy": map[string]any{
+								"title":       "pullPolicy",
+								"type":        "string",
+								"default":     "IfNotPresent",
+								"enum":        []any{"Always", "IfNotPresent", "Never"},
+								"description": "Image pull policy",
+							},
+						},
+					},
+					"serviceAccount": map[string]any{
+						"title":       "serviceAccount",
+						"type":        "string",
+						"default":     "default",
+						"description": "some commented out notes",
+					},
+				},
+				"type": "object",
+			},
+		},
 	}
 
 	for _, tc := range tests {
@@ -66,6 +112,34 @@ func TestHelmReadProvisionerInputs(t *testing.T) {
 	}
 }
 
+func TestHelmReadProvisionerUI(t *testing.T) {
+	testDir := t.TempDir()
+
+	content, err := os.ReadFile(path.Join("testdata", "helm", "documented.yaml"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err = os.WriteFile(path.Join(testDir, "values.yaml"), content, 0644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	prov := provisioners.HelmProvisioner{}
+	got, err := prov.ReadProvisionerUI(testDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]any{
+		"ui:order": []any{"replicaCount", "image", "serviceAccount"},
+		"image": map[string]any{
+			"ui:order": []any{"repository", "tag", "pullPolicy"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
 func TestHelmInitializeStep(t *testing.T) {
 	type test struct {
 		name      string
@@ -14,7 +14,26 @@ import (
 type OpentofuProvisioner struct{}
 
 // ExportMassdriverInputs generates the _massdriver_variables.tf file from the massdriver schema.
-func (p *OpentofuProvisioner) ExportMassdriverInputs(stepPath string, variables map[string]any) (retErr error) {
+func (p *OpentofuProvisioner) ExportMassdriverInputs(stepPath string, variables map[string]any) error {
+	return p.ExportMassdriverInputsWithOptions(stepPath, variables, ExportOptions{})
+}
+
+// ExportMassdriverInputsWithOptions generates the _massdriver_variables.tf file from the massdriver
+// schema, typing connection variables from opts.Connections. When opts.Artifacts is set it also
+// scaffolds a _massdriver_outputs.tf file with a massdriver_artifact resource per artifact.
+func (p *OpentofuProvisioner) ExportMassdriverInputsWithOptions(stepPath string, variables map[string]any, opts ExportOptions) error {
+	if err := exportTofuVariables(stepPath, typeConnectionVariables(variables, opts.Connections)); err != nil {
+		return err
+	}
+
+	if opts.Artifacts != nil {
+		return exportTofuOutputs(stepPath, opts.Artifacts)
+	}
+
+	return nil
+}
+
+func exportTofuVariables(stepPath string, variables map[string]any) (retErr error) {
 	massdriverVarsFile := filepath.Join(stepPath, "_massdriver_variables.tf")
 	massdriverVarsBackup := massdriverVarsFile + ".bak"
 
@@ -90,6 +109,23 @@ func (p *OpentofuProvisioner) ReadProvisionerInputs(stepPath string) (map[string
 	return variables, nil
 }
 
+// ReadProvisionerOutputs returns the names of all OpenTofu outputs declared in the step directory.
+func (p *OpentofuProvisioner) ReadProvisionerOutputs(stepPath string) ([]string, error) {
+	blocks, err := tofuBlocks(stepPath)
+	if err != nil {
+		return nil, err
+	}
+
+	outputs := []string{}
+	for _, block := range blocks {
+		if block.Type == "output" && len(block.Labels) == 1 {
+			outputs = append(outputs, block.Labels[0])
+		}
+	}
+
+	return outputs, nil
+}
+
 // InitializeStep copies the OpenTofu module directory into the step directory, excluding state files.
 func (p *OpentofuProvisioner) InitializeStep(stepPath string, sourcePath string) error {
 	pathInfo, statErr := os.Stat(sourcePath)
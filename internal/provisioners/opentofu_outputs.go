@@ -0,0 +1,159 @@
+package provisioners
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const massdriverOutputsFile = "_massdriver_outputs.tf"
+
+const massdriverOutputsHeader = "// This file was scaffolded by massdriver from the artifacts in your massdriver.yaml file.\n// It is only generated when missing, so edit it freely: replace each null with a value from your module.\n"
+
+// exportTofuOutputs scaffolds _massdriver_outputs.tf with a massdriver_artifact resource for
+// every artifact the step doesn't already declare. An existing file is never overwritten.
+func exportTofuOutputs(stepPath string, artifacts map[string]any) error {
+	outputsFile := filepath.Join(stepPath, massdriverOutputsFile)
+	if _, statErr := os.Stat(outputsFile); statErr == nil {
+		return nil
+	} else if !errors.Is(statErr, os.ErrNotExist) {
+		return statErr
+	}
+
+	artifactProps, ok := artifacts["properties"].(map[string]any)
+	if !ok || len(artifactProps) == 0 {
+		return nil
+	}
+
+	declared, declaredErr := declaredArtifactFields(stepPath)
+	if declaredErr != nil {
+		return declaredErr
+	}
+
+	names := make([]string, 0, len(artifactProps))
+	for name := range artifactProps {
+		if !slices.Contains(declared, name) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	sort.Strings(names)
+
+	f := hclwrite.NewEmptyFile()
+	body := f.Body()
+	for i, name := range names {
+		if i > 0 {
+			body.AppendNewline()
+		}
+		artifact, _ := artifactProps[name].(map[string]any)
+		resource := body.AppendNewBlock("resource", []string{"massdriver_artifact", name}).Body()
+		resource.SetAttributeValue("field", cty.StringVal(name))
+		resource.SetAttributeValue("name", cty.StringVal(artifactTitle(name, artifact)))
+		resource.SetAttributeRaw("artifact", hclwrite.TokensForFunctionCall("jsonencode", artifactStubTokens(tightenTofuType(artifact))))
+	}
+
+	content := append([]byte(massdriverOutputsHeader), f.Bytes()...)
+	return os.WriteFile(outputsFile, content, 0600)
+}
+
+// declaredArtifactFields returns the field of every massdriver_artifact resource already
+// declared in the step's OpenTofu files.
+func declaredArtifactFields(stepPath string) ([]string, error) {
+	blocks, err := tofuBlocks(stepPath)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := []string{}
+	for _, block := range blocks {
+		if block.Type != "resource" || len(block.Labels) < 2 || block.Labels[0] != "massdriver_artifact" {
+			continue
+		}
+		if field, fieldOk := literalStringAttribute(block.Body, "field"); fieldOk {
+			fields = append(fields, field)
+		}
+	}
+
+	return fields, nil
+}
+
+// tofuBlocks parses every .tf file in stepPath and returns their top-level blocks.
+func tofuBlocks(stepPath string) ([]*hclsyntax.Block, error) {
+	tfFiles, globErr := filepath.Glob(filepath.Join(stepPath, "*.tf"))
+	if globErr != nil {
+		return nil, globErr
+	}
+
+	parser := hclparse.NewParser()
+	blocks := []*hclsyntax.Block{}
+	for _, tfFile := range tfFiles {
+		file, diags := parser.ParseHCLFile(tfFile)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("failed to parse %s: %w", tfFile, diags)
+		}
+		if body, ok := file.Body.(*hclsyntax.Body); ok {
+			blocks = append(blocks, body.Blocks...)
+		}
+	}
+
+	return blocks, nil
+}
+
+func literalStringAttribute(body *hclsyntax.Body, name string) (string, bool) {
+	attr, ok := body.Attributes[name]
+	if !ok {
+		return "", false
+	}
+	val, diags := attr.Expr.Value(&hcl.EvalContext{})
+	if diags.HasErrors() || val.IsNull() || !val.Type().Equals(cty.String) {
+		return "", false
+	}
+	return val.AsString(), true
+}
+
+func artifactTitle(name string, artifact map[string]any) string {
+	if title, ok := artifact["title"].(string); ok && title != "" {
+		return title
+	}
+	return strings.ReplaceAll(name, "_", " ")
+}
+
+// artifactStubTokens renders an HCL value mirroring the shape of the artifact schema,
+// with null placeholders for every leaf.
+func artifactStubTokens(node map[string]any) hclwrite.Tokens {
+	props, ok := node["properties"].(map[string]any)
+	if !ok {
+		if node["type"] == "array" {
+			return hclwrite.TokensForTuple(nil)
+		}
+		return hclwrite.TokensForIdentifier("null")
+	}
+
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	attrs := make([]hclwrite.ObjectAttrTokens, 0, len(names))
+	for _, name := range names {
+		prop, _ := props[name].(map[string]any)
+		attrs = append(attrs, hclwrite.ObjectAttrTokens{
+			Name:  hclwrite.TokensForIdentifier(name),
+			Value: artifactStubTokens(prop),
+		})
+	}
+	return hclwrite.TokensForObject(attrs)
+}
@@ -2,6 +2,7 @@ package provisioners_test
 
 import (
 	"errors"
+	"maps"
 	"os"
 	"path"
 	"reflect"
@@ -12,6 +13,8 @@ import (
 	"github.com/massdriver-cloud/mass/internal/provisioners"
 )
 
+const outputsScaffoldHeader = "// This file was scaffolded by massdriver from the artifacts in your massdriver.yaml file.\n// It is only generated when missing, so edit it freely: replace each null with a value from your module.\n"
+
 const autoGeneratedHeader = "// This file is auto-generated by massdriver from your massdriver.yaml file.\n// Any changes made directly to this file will be overwritten on the next build.\n// To opt a variable out of regeneration, move it to another file (e.g. variables.tf).\n"
 
 func TestOpentofuExportMassdriverInputs(t *testing.T) {
@@ -228,6 +231,29 @@ func TestOpentofuReadProvisionerInputs(t *testing.T) {
 	}
 }
 
+func TestOpentofuReadProvisionerOutputs(t *testing.T) {
+	testDir := t.TempDir()
+
+	content, err := os.ReadFile(path.Join("testdata", "opentofu", "outputs.tf"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err = os.WriteFile(path.Join(testDir, "main.tf"), content, 0644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	prov := provisioners.OpentofuProvisioner{}
+	got, err := prov.ReadProvisionerOutputs(testDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"bar"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
 func TestOpentofuInitializeStep(t *testing.T) {
 	type test struct {
 		name       string
@@ -277,3 +303,184 @@ func TestOpentofuInitializeStep(t *testing.T) {
 		})
 	}
 }
+
+func TestOpentofuExportTypedConnections(t *testing.T) {
+	network := map[string]any{
+		"allOf": []any{
+			map[string]any{
+				"required": []any{"id"},
+				"properties": map[string]any{
+					"id": map[string]any{"type": []any{"string", "null"}},
+				},
+			},
+			map[string]any{
+				"properties": map[string]any{
+					"tags": map[string]any{
+						"type":                 "object",
+						"additionalProperties": map[string]any{"type": "string"},
+					},
+				},
+			},
+		},
+	}
+	openNetwork := maps.Clone(network)
+	openNetwork["additionalProperties"] = true
+
+	type test struct {
+		name       string
+		connection map[string]any
+		want       string
+	}
+	tests := []test{
+		{
+			name:       "types connections as objects",
+			connection: network,
+			want: autoGeneratedHeader + `variable "network" {
+  type = object({
+    id   = string
+    tags = optional(map(string))
+  })
+}
+`,
+		},
+		{
+			name:       "leaves explicitly open connections as any",
+			connection: openNetwork,
+			want: autoGeneratedHeader + `variable "network" {
+  type = any
+}
+`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			variables := map[string]any{
+				"required": []any{"bar", "foo", "network"},
+				"properties": map[string]any{
+					"bar":     map[string]any{"type": "string"},
+					"foo":     map[string]any{"type": "string"},
+					"network": tc.connection,
+				},
+			}
+			connections := map[string]any{
+				"properties": map[string]any{"network": tc.connection},
+			}
+
+			testDir := t.TempDir()
+			content, err := os.ReadFile(path.Join("testdata", "opentofu", "same.tf"))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err = os.WriteFile(path.Join(testDir, "variables.tf"), content, 0644); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			prov := provisioners.OpentofuProvisioner{}
+			if err = prov.ExportMassdriverInputsWithOptions(testDir, variables, provisioners.ExportOptions{Connections: connections}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got, err := os.ReadFile(path.Join(testDir, "_massdriver_variables.tf"))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != tc.want {
+				t.Errorf("got %s want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestOpentofuExportMassdriverOutputs(t *testing.T) {
+	artifacts := map[string]any{
+		"properties": map[string]any{
+			"bucket": map[string]any{
+				"title": "S3 Bucket",
+				"properties": map[string]any{
+					"arn":    map[string]any{"type": "string"},
+					"region": map[string]any{"type": "string"},
+				},
+			},
+			"queue": map[string]any{
+				"properties": map[string]any{
+					"urls": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+				},
+			},
+		},
+	}
+
+	type test struct {
+		name          string
+		existingFiles map[string]string
+		want          string
+	}
+	tests := []test{
+		{
+			name: "scaffolds every artifact",
+			want: outputsScaffoldHeader + `resource "massdriver_artifact" "bucket" {
+  field = "bucket"
+  name  = "S3 Bucket"
+  artifact = jsonencode({
+    arn    = null
+    region = null
+  })
+}
+
+resource "massdriver_artifact" "queue" {
+  field = "queue"
+  name  = "queue"
+  artifact = jsonencode({
+    urls = []
+  })
+}
+`,
+		},
+		{
+			name: "skips artifacts declared elsewhere",
+			existingFiles: map[string]string{
+				"artifacts.tf": "resource \"massdriver_artifact\" \"queue\" {\n  field = \"queue\"\n}\n",
+			},
+			want: outputsScaffoldHeader + `resource "massdriver_artifact" "bucket" {
+  field = "bucket"
+  name  = "S3 Bucket"
+  artifact = jsonencode({
+    arn    = null
+    region = null
+  })
+}
+`,
+		},
+		{
+			name: "leaves an existing file alone",
+			existingFiles: map[string]string{
+				"_massdriver_outputs.tf": "# hand written\n",
+			},
+			want: "# hand written\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			testDir := t.TempDir()
+			for name, content := range tc.existingFiles {
+				if err := os.WriteFile(path.Join(testDir, name), []byte(content), 0644); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			prov := provisioners.OpentofuProvisioner{}
+			if err := prov.ExportMassdriverInputsWithOptions(testDir, map[string]any{"properties": map[string]any{}}, provisioners.ExportOptions{Artifacts: artifacts}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got, err := os.ReadFile(path.Join(testDir, "_massdriver_outputs.tf"))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != tc.want {
+				t.Errorf("got %s want %s", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package provisioners
+
+import (
+	"maps"
+	"slices"
+)
+
+// typeConnectionVariables returns a copy of variables in which every connection variable
+// has been rewritten so airlock can transpile it to a precise OpenTofu type. Resource-type
+// schemas often lean on JSON Schema features (allOf, nullable type lists, implicit object
+// types, open objects) that have no HCL equivalent and otherwise collapse to `any`.
+func typeConnectionVariables(variables map[string]any, connections map[string]any) map[string]any {
+	connProps, connOk := connections["properties"].(map[string]any)
+	varProps, varOk := variables["properties"].(map[string]any)
+	if !connOk || !varOk || len(connProps) == 0 {
+		return variables
+	}
+
+	typedProps := maps.Clone(varProps)
+	for name := range connProps {
+		if prop, ok := varProps[name].(map[string]any); ok {
+			typedProps[name] = tightenTofuType(prop)
+		}
+	}
+
+	typed := maps.Clone(variables)
+	typed["properties"] = typedProps
+	return typed
+}
+
+// tightenTofuType returns a copy of node with allOf merged in, nullable type lists
+// collapsed, and implicit object and array types made explicit. Objects that are
+// explicitly open, through additionalProperties or patternProperties, are left open:
+// OpenTofu drops undeclared attributes when converting to an object type, so modules
+// reading fields the schema doesn't declare need the variable to stay `any`.
+func tightenTofuType(node map[string]any) map[string]any {
+	out := maps.Clone(node)
+	if out == nil {
+		out = map[string]any{}
+	}
+
+	if allOf, ok := out["allOf"].([]any); ok {
+		delete(out, "allOf")
+		for _, sub := range allOf {
+			if subSchema, subOk := sub.(map[string]any); subOk {
+				mergeTofuSchema(out, tightenTofuType(subSchema))
+			}
+		}
+	}
+
+	if typeList, ok := out["type"].([]any); ok {
+		nonNull := slices.DeleteFunc(slices.Clone(typeList), func(t any) bool { return t == "null" })
+		if len(nonNull) == 1 {
+			out["type"] = nonNull[0]
+		} else {
+			delete(out, "type")
+		}
+	}
+
+	if _, hasType := out["type"]; !hasType {
+		if _, hasProps := out["properties"]; hasProps {
+			out["type"] = "object"
+		} else if _, hasItems := out["items"]; hasItems {
+			out["type"] = "array"
+		}
+	}
+
+	if props, ok := out["properties"].(map[string]any); ok {
+		typedProps := make(map[string]any, len(props))
+		for name, prop := range props {
+			if propSchema, propOk := prop.(map[string]any); propOk {
+				typedProps[name] = tightenTofuType(propSchema)
+			} else {
+				typedProps[name] = prop
+			}
+		}
+		out["properties"] = typedProps
+	}
+
+	if items, ok := out["items"].(map[string]any); ok {
+		out["items"] = tightenTofuType(items)
+	}
+	if additional, ok := out["additionalProperties"].(map[string]any); ok {
+		out["additionalProperties"] = tightenTofuType(additional)
+	}
+
+	return out
+}
+
+// mergeTofuSchema folds the properties, required list and type of src into dst.
+func mergeTofuSchema(dst, src map[string]any) {
+	if srcProps, ok := src["properties"].(map[string]any); ok {
+		dstProps, _ := dst["properties"].(map[string]any)
+		merged := maps.Clone(dstProps)
+		if merged == nil {
+			merged = map[string]any{}
+		}
+		for name, prop := range srcProps {
+			if _, exists := merged[name]; !exists {
+				merged[name] = prop
+			}
+		}
+		dst["properties"] = merged
+	}
+
+	if srcReq, ok := src["required"].([]any); ok {
+		dstReq, _ := dst["required"].([]any)
+		merged := slices.Clone(dstReq)
+		for _, name := range srcReq {
+			if !slices.Contains(merged, name) {
+				merged = append(merged, name)
+			}
+		}
+		dst["required"] = merged
+	}
+
+	if _, hasType := dst["type"]; !hasType {
+		if srcType, ok := src["type"]; ok {
+			dst["type"] = srcType
+		}
+	}
+}
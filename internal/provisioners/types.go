@@ -40,3 +40,30 @@ func (p *NoopProvisioner) ReadProvisionerInputs(string) (map[string]any, error)
 func (p *NoopProvisioner) InitializeStep(string, string) error {
 	return nil
 }
+
+// OutputReader is implemented by provisioners that can list the outputs a step declares.
+type OutputReader interface {
+	ReadProvisionerOutputs(stepPath string) ([]string, error)
+}
+
+// UIReader is implemented by provisioners that can suggest a UI schema, such as field order,
+// for the inputs they read.
+type UIReader interface {
+	ReadProvisionerUI(stepPath string) (map[string]any, error)
+}
+
+// ExportOptions carries bundle context that lets a provisioner generate more than
+// plain variable declarations.
+type ExportOptions struct {
+	// Connections is the bundle's dereferenced connections schema. Provisioners use it
+	// to derive precise types for connection variables.
+	Connections map[string]any
+	// Artifacts is the bundle's dereferenced artifacts schema. When set, provisioners
+	// that support it scaffold an output declaration for each artifact.
+	Artifacts map[string]any
+}
+
+// OptionsExporter is implemented by provisioners that accept ExportOptions.
+type OptionsExporter interface {
+	ExportMassdriverInputsWithOptions(stepPath string, variables map[string]any, opts ExportOptions) error
+}
@@ -13,6 +13,7 @@ import (
 // This is an experimental format that provides a more ergonomic authoring experience.
 type MassdriverYAML struct {
 	Name    string         `yaml:"name"`
+	Version string         `yaml:"version"`
 	Label   string         `yaml:"label"`
 	Icon    string         `yaml:"icon"`
 	UI      *UIConfig      `yaml:"ui"`
@@ -44,14 +45,9 @@ type ExportConfig struct {
 // Build reads a massdriver.yaml file and builds it into the resource type
 // format expected by the Massdriver API.
 func Build(path string) (map[string]any, error) {
-	content, err := os.ReadFile(path)
+	config, err := readMassdriverYAML(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read massdriver.yaml: %w", err)
-	}
-
-	var config MassdriverYAML
-	if err := yaml.Unmarshal(content, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse massdriver.yaml: %w", err)
+		return nil, err
 	}
 
 	baseDir := filepath.Dir(path)
@@ -122,6 +118,19 @@ func Build(path string) (map[string]any, error) {
 	return result, nil
 }
 
+func readMassdriverYAML(path string) (*MassdriverYAML, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read massdriver.yaml: %w", err)
+	}
+
+	var config MassdriverYAML
+	if err := yaml.Unmarshal(content, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse massdriver.yaml: %w", err)
+	}
+	return &config, nil
+}
+
 // IsMassdriverYAMLResourceType checks if the given path is a massdriver.yaml
 // file that should be treated as a resource type in the experimental format.
 func IsMassdriverYAMLResourceType(path string) bool {
@@ -0,0 +1,131 @@
+package resourcetype
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ResourceTypeCacheDirEnv, when set, is the directory published resource-type schemas
+// are cached in between runs by [NewMassdriverResolver].
+const ResourceTypeCacheDirEnv = "MASSDRIVER_RESOURCE_TYPE_CACHE_DIR"
+
+// DiskCacheTTL is how long a resource type cached on disk is used before it's fetched again.
+const DiskCacheTTL = time.Hour
+
+// RefCache holds the documents $refs resolved to during dereferencing, keyed by
+// absolute file path, URL or resource-type name. It is safe for concurrent use.
+type RefCache struct {
+	mu   sync.Mutex
+	docs map[string]map[string]any
+}
+
+// NewRefCache returns an empty RefCache.
+func NewRefCache() *RefCache {
+	return &RefCache{docs: map[string]map[string]any{}}
+}
+
+// load returns a copy of the document cached under key, fetching and caching it first
+// if needed. Copies are returned because dereferencing modifies the maps it walks.
+func (c *RefCache) load(key string, fetch func() (map[string]any, error)) (map[string]any, error) {
+	c.mu.Lock()
+	doc, cached := c.docs[key]
+	c.mu.Unlock()
+	if !cached {
+		var err error
+		if doc, err = fetch(); err != nil {
+			return nil, err
+		}
+		c.mu.Lock()
+		c.docs[key] = doc
+		c.mu.Unlock()
+	}
+	copied, _ := copyJSON(doc).(map[string]any)
+	return copied, nil
+}
+
+// copyJSON deep copies a decoded JSON value.
+func copyJSON(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		copied := make(map[string]any, len(v))
+		for key, item := range v {
+			copied[key] = copyJSON(item)
+		}
+		return copied
+	case []any:
+		copied := make([]any, len(v))
+		for i, item := range v {
+			copied[i] = copyJSON(item)
+		}
+		return copied
+	default:
+		return value
+	}
+}
+
+// NewDiskCachedResolver wraps resolver with a cache of resource types in dir. A cached
+// resource type younger than ttl is used instead of calling resolver. If resolver fails,
+// an expired copy is used when there is one, so builds keep working offline.
+func NewDiskCachedResolver(resolver func(context.Context, string) (map[string]any, error), dir string, ttl time.Duration) func(context.Context, string) (map[string]any, error) {
+	return func(ctx context.Context, name string) (map[string]any, error) {
+		path := filepath.Join(dir, url.PathEscape(name)+".json")
+
+		cached, modTime, readErr := readCachedResourceType(path)
+		if readErr == nil && time.Since(modTime) < ttl {
+			return cached, nil
+		}
+
+		resourceType, err := resolver(ctx, name)
+		if err != nil {
+			if readErr == nil {
+				return cached, nil
+			}
+			return nil, err
+		}
+
+		// the cache is best effort; a resource type that can't be cached is still returned
+		_ = writeCachedResourceType(path, resourceType)
+		return resourceType, nil
+	}
+}
+
+func readCachedResourceType(path string) (map[string]any, time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	resourceType, err := readJSONFile(path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return resourceType, info.ModTime(), nil
+}
+
+func writeCachedResourceType(path string, resourceType map[string]any) error {
+	data, err := json.Marshal(resourceType)
+	if err != nil {
+		return err
+	}
+	if mkdirErr := os.MkdirAll(filepath.Dir(path), 0755); mkdirErr != nil {
+		return mkdirErr
+	}
+	// write to a temporary file and rename it so concurrent runs never read a partial file
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".resource-type-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, writeErr := tmp.Write(data); writeErr != nil {
+		tmp.Close()
+		return writeErr
+	}
+	if closeErr := tmp.Close(); closeErr != nil {
+		return closeErr
+	}
+	return os.Rename(tmp.Name(), path)
+}
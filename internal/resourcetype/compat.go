@@ -0,0 +1,52 @@
+package resourcetype
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+
+	"github.com/massdriver-cloud/mass/internal/jsonschema"
+	"github.com/massdriver-cloud/massdriver-sdk-go/massdriver/platform/resources"
+)
+
+// Incompatibility is an existing resource whose payload doesn't validate against a
+// resource type schema.
+type Incompatibility struct {
+	ResourceID   string
+	ResourceName string
+	Messages     []string
+}
+
+// Name returns the $md.name of a resource type schema.
+func Name(schema map[string]any) (string, error) {
+	md, _ := schema["$md"].(map[string]any)
+	name, _ := md["name"].(string)
+	if name == "" {
+		return "", errors.New("resource type is missing $md.name")
+	}
+	return name, nil
+}
+
+// CheckCompat validates the payload of each resource in seq against schema and
+// returns the resources whose payloads no longer validate.
+func CheckCompat(schema map[string]any, seq iter.Seq2[resources.Resource, error]) ([]Incompatibility, error) {
+	sch, schemaErr := jsonschema.LoadSchemaFromGo(schema)
+	if schemaErr != nil {
+		return nil, fmt.Errorf("failed to compile resource type schema: %w", schemaErr)
+	}
+
+	incompatible := []Incompatibility{}
+	for res, iterErr := range seq {
+		if iterErr != nil {
+			return nil, fmt.Errorf("failed to list resources: %w", iterErr)
+		}
+		if validateErr := jsonschema.ValidateGo(sch, res.Payload); validateErr != nil {
+			incompatible = append(incompatible, Incompatibility{
+				ResourceID:   res.ID,
+				ResourceName: res.Name,
+				Messages:     jsonschema.ValidationMessages(validateErr),
+			})
+		}
+	}
+	return incompatible, nil
+}
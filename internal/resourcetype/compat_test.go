@@ -0,0 +1,93 @@
+package resourcetype_test
+
+import (
+	"errors"
+	"iter"
+	"reflect"
+	"testing"
+
+	"github.com/massdriver-cloud/mass/internal/resourcetype"
+
+	"github.com/massdriver-cloud/massdriver-sdk-go/massdriver/platform/resources"
+)
+
+func resourceSeq(res []resources.Resource, err error) iter.Seq2[resources.Resource, error] {
+	return func(yield func(resources.Resource, error) bool) {
+		for _, r := range res {
+			if !yield(r, nil) {
+				return
+			}
+		}
+		if err != nil {
+			yield(resources.Resource{}, err)
+		}
+	}
+}
+
+func TestCheckCompat(t *testing.T) {
+	schema := map[string]any{
+		"$md":      map[string]any{"name": "foo"},
+		"type":     "object",
+		"required": []any{"host", "port"},
+		"properties": map[string]any{
+			"host": map[string]any{"type": "string"},
+			"port": map[string]any{"type": "integer"},
+		},
+	}
+
+	type test struct {
+		name      string
+		resources []resources.Resource
+		want      []resourcetype.Incompatibility
+	}
+	tests := []test{
+		{
+			name: "all compatible",
+			resources: []resources.Resource{
+				{ID: "r1", Name: "one", Payload: map[string]any{"host": "db", "port": 5432}},
+			},
+			want: []resourcetype.Incompatibility{},
+		},
+		{
+			name: "lists resources that no longer validate",
+			resources: []resources.Resource{
+				{ID: "r1", Name: "one", Payload: map[string]any{"host": "db", "port": 5432}},
+				{ID: "r2", Name: "two", Payload: map[string]any{"host": "db"}},
+				{ID: "r3", Name: "three", Payload: map[string]any{"host": "db", "port": "5432"}},
+			},
+			want: []resourcetype.Incompatibility{
+				{ResourceID: "r2", ResourceName: "two", Messages: []string{"at '/': missing property 'port'"}},
+				{ResourceID: "r3", ResourceName: "three", Messages: []string{"at '/port': got string, want integer"}},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := resourcetype.CheckCompat(schema, resourceSeq(tc.resources, nil))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	t.Run("list error", func(t *testing.T) {
+		_, err := resourcetype.CheckCompat(schema, resourceSeq(nil, errors.New("boom")))
+		if err == nil || err.Error() != "failed to list resources: boom" {
+			t.Errorf("got %v, want list error", err)
+		}
+	})
+}
+
+func TestName(t *testing.T) {
+	name, err := resourcetype.Name(map[string]any{"$md": map[string]any{"name": "foo"}})
+	if err != nil || name != "foo" {
+		t.Errorf("got %q, %v, want foo", name, err)
+	}
+	if _, err = resourcetype.Name(map[string]any{}); err == nil {
+		t.Error("expected an error for a schema without $md.name")
+	}
+}
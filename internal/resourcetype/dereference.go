@@ -7,10 +7,14 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"reflect"
 	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/massdriver-cloud/massdriver-sdk-go/massdriver"
 )
@@ -20,28 +24,64 @@ import (
 // Resolver fetches a published resource-type schema by name; tests inject a
 // stub directly, production callers use [NewMassdriverResolver] (or build a
 // closure around [GetAsMap]).
+//
+// OnFile, when set, is called with the absolute path of every local file read
+// while resolving relative $refs, e.g. to watch them for changes.
+//
+// Cache holds the documents $refs resolved to, so each file, URL and resource
+// type is only fetched once. DereferenceSchema creates one per call when it is
+// nil; pass a shared [NewRefCache] to also dedupe across calls.
 type DereferenceOptions struct {
 	Resolver func(ctx context.Context, name string) (map[string]any, error)
 	Cwd      string
 	StripID  bool
+	OnFile   func(path string)
+	Cache    *RefCache
+
+	// chain holds the refs being resolved, outermost first, to detect cycles.
+	chain []string
+	// document is the unmodified document being walked, which "#/..." fragment refs
+	// point into, and documentKey its file path or URL. The key is empty for the
+	// document passed to DereferenceSchema.
+	document    any
+	documentKey string
+	// baseURL is set while walking a document fetched over HTTP, so relative refs in
+	// it resolve to URLs next to it rather than to local files.
+	baseURL *url.URL
 }
 
 // NewMassdriverResolver returns a Resolver bound to a *massdriver.Client that
-// looks up resource-type schemas via the legacy GraphQL surface.
+// looks up resource-type schemas via the legacy GraphQL surface. When
+// [ResourceTypeCacheDirEnv] is set, resource types are also cached on disk there
+// for [DiskCacheTTL].
 func NewMassdriverResolver(c *massdriver.Client) func(context.Context, string) (map[string]any, error) {
-	return func(ctx context.Context, name string) (map[string]any, error) {
+	resolver := func(ctx context.Context, name string) (map[string]any, error) {
 		return GetAsMap(ctx, c, name)
 	}
+	if dir := os.Getenv(ResourceTypeCacheDirEnv); dir != "" {
+		return NewDiskCachedResolver(resolver, dir, DiskCacheTTL)
+	}
+	return resolver
 }
 
 // relativeFilePathPattern only accepts relative file path prefixes "./" and "../"
 var relativeFilePathPattern = regexp.MustCompile(`^(\.\/|\.\.\/)`)
 var massdriverResourceTypePattern = regexp.MustCompile(`^[a-zA-Z0-9-]+(\/[a-zA-Z0-9-]+)?$`)
 var httpPattern = regexp.MustCompile(`^(http|https)://`)
-var fragmentPattern = regexp.MustCompile(`^#`)
 
-// DereferenceSchema recursively resolves $ref pointers in a schema value.
+// DereferenceSchema recursively resolves $ref pointers in a schema value. Refs may
+// end in a JSON Pointer fragment, like ./library.json#/$defs/name, to use part of a
+// document, and "#/..." refs point into the document they're in. A $ref that leads
+// back to a document it's already inside of is reported as an error with the chain
+// of refs that formed the cycle, except for fragment refs in anyVal itself, which
+// are left as-is since they still point to the same place.
 func DereferenceSchema(anyVal any, opts DereferenceOptions) (any, error) {
+	if opts.Cache == nil {
+		opts.Cache = NewRefCache()
+	}
+	if opts.document == nil {
+		opts.document = copyJSON(anyVal)
+	}
 	val := getValue(anyVal)
 
 	switch val.Kind() { //nolint:exhaustive // only slice/array and map need dereferencing; other kinds returned as-is
@@ -65,17 +105,27 @@ func DereferenceSchema(anyVal any, opts DereferenceOptions) (any, error) {
 			}
 
 			var err error
-			if relativeFilePathPattern.MatchString(schemaRefValue) { //nolint:gocritic // long if-else chain matches ref types; restructuring reduces readability
+			docRef, fragment, _ := strings.Cut(schemaRefValue, "#")
+			if docRef == "" { //nolint:gocritic // long if-else chain matches ref types; restructuring reduces readability
+				// a fragment ref into the document being walked
+				hydratedSchema, err = dereferenceFragmentRef(hydratedSchema, schema, schemaRefValue, fragment, opts)
+			} else if relativeFilePathPattern.MatchString(docRef) && opts.baseURL != nil {
+				// a relative ref in a document fetched over HTTP is another URL next to it
+				refURL, parseErr := url.Parse(docRef)
+				if parseErr != nil {
+					return nil, parseErr
+				}
+				hydratedSchema, err = dereferenceHTTPRef(hydratedSchema, schema, opts.baseURL.ResolveReference(refURL).String(), fragment, opts)
+			} else if relativeFilePathPattern.MatchString(docRef) {
 				// this is a relative file ref
 				// build up the path from where the dir current schema was read
-				hydratedSchema, err = dereferenceFilePathRef(hydratedSchema, schema, schemaRefValue, opts)
-			} else if httpPattern.MatchString(schemaRefValue) {
+				hydratedSchema, err = dereferenceFilePathRef(hydratedSchema, schema, docRef, fragment, opts)
+			} else if httpPattern.MatchString(docRef) {
 				// HTTP ref. Pull the schema down via HTTP GET and hydrate
-				hydratedSchema, err = dereferenceHTTPRef(hydratedSchema, schema, schemaRefValue, opts)
-			} else if massdriverResourceTypePattern.MatchString(schemaRefValue) {
+				hydratedSchema, err = dereferenceHTTPRef(hydratedSchema, schema, docRef, fragment, opts)
+			} else if massdriverResourceTypePattern.MatchString(docRef) {
 				// this must be a published schema, so fetch from massdriver
-				hydratedSchema, err = dereferenceMassdriverRef(hydratedSchema, schema, schemaRefValue, opts)
-			} else if fragmentPattern.MatchString(schemaRefValue) { //nolint:revive // fragment refs are intentionally left as-is
+				hydratedSchema, err = dereferenceMassdriverRef(hydratedSchema, schema, docRef, fragment, opts)
 			} else {
 				return nil, fmt.Errorf("unable to resolve ref: %s", schemaRefValue)
 			}
@@ -113,11 +163,38 @@ func dereferenceList(val reflect.Value, opts DereferenceOptions) ([]any, error)
 	return hydratedList, nil
 }
 
-func dereferenceMassdriverRef(hydratedSchema map[string]any, schema map[string]any, schemaRefValue string, opts DereferenceOptions) (map[string]any, error) {
+// dereferenceFragmentRef hydrates schema with the part of the current document that
+// fragment points to.
+func dereferenceFragmentRef(hydratedSchema map[string]any, schema map[string]any, schemaRefValue string, fragment string, opts DereferenceOptions) (map[string]any, error) {
+	if opts.documentKey == "" {
+		// in the document being dereferenced, a ref to the whole document or one that
+		// recurses can stay, since it still points to the same place
+		if fragment == "" || slices.Contains(opts.chain, schemaRefValue) {
+			return hydratedSchema, nil
+		}
+	}
+	opts, err := opts.enter(refKey(opts.documentKey, fragment))
+	if err != nil {
+		return hydratedSchema, err
+	}
+	referencedSchema, err := refTarget(opts.document, schemaRefValue, fragment)
+	if err != nil {
+		return hydratedSchema, err
+	}
+	return replaceRef(schema, referencedSchema, opts)
+}
+
+func dereferenceMassdriverRef(hydratedSchema map[string]any, schema map[string]any, schemaRefValue string, fragment string, opts DereferenceOptions) (map[string]any, error) {
 	if opts.Resolver == nil {
 		return hydratedSchema, fmt.Errorf("cannot resolve massdriver ref %q: no resolver configured", schemaRefValue)
 	}
-	referencedSchema, err := opts.Resolver(context.Background(), schemaRefValue)
+	opts, err := opts.enter(refKey(schemaRefValue, fragment))
+	if err != nil {
+		return hydratedSchema, err
+	}
+	referencedSchema, err := opts.Cache.load("massdriver:"+schemaRefValue, func() (map[string]any, error) {
+		return opts.Resolver(context.Background(), schemaRefValue)
+	})
 	if err != nil {
 		return hydratedSchema, err
 	}
@@ -137,6 +214,10 @@ func dereferenceMassdriverRef(hydratedSchema map[string]any, schema map[string]a
 		delete(referencedSchema, "$id")
 	}
 
+	opts.document, opts.documentKey, opts.baseURL = referencedSchema, schemaRefValue, nil
+	if referencedSchema, err = refTarget(referencedSchema, refKey(schemaRefValue, fragment), fragment); err != nil {
+		return hydratedSchema, err
+	}
 	hydratedSchema, err = replaceRef(schema, referencedSchema, opts)
 	if err != nil {
 		return hydratedSchema, err
@@ -144,39 +225,56 @@ func dereferenceMassdriverRef(hydratedSchema map[string]any, schema map[string]a
 	return hydratedSchema, nil
 }
 
-func dereferenceHTTPRef(hydratedSchema map[string]any, schema map[string]any, schemaRefValue string, opts DereferenceOptions) (map[string]any, error) {
-	ctx := context.Background()
-	var referencedSchema map[string]any
+func dereferenceHTTPRef(hydratedSchema map[string]any, schema map[string]any, schemaRefValue string, fragment string, opts DereferenceOptions) (map[string]any, error) {
+	refURL, err := url.Parse(schemaRefValue)
+	if err != nil {
+		return hydratedSchema, err
+	}
+	opts, err = opts.enter(refKey(schemaRefValue, fragment))
+	if err != nil {
+		return hydratedSchema, err
+	}
+	referencedSchema, err := opts.Cache.load(schemaRefValue, func() (map[string]any, error) {
+		return fetchJSON(schemaRefValue)
+	})
+	if err != nil {
+		return hydratedSchema, err
+	}
+
+	opts.document, opts.documentKey, opts.baseURL = referencedSchema, schemaRefValue, refURL
+	if referencedSchema, err = refTarget(referencedSchema, refKey(schemaRefValue, fragment), fragment); err != nil {
+		return hydratedSchema, err
+	}
+	hydratedSchema, err = replaceRef(schema, referencedSchema, opts)
+	return hydratedSchema, err
+}
+
+func fetchJSON(url string) (map[string]any, error) {
+	var result map[string]any
 
 	client := http.Client{}
-	request, err := http.NewRequestWithContext(ctx, http.MethodGet, schemaRefValue, nil)
+	request, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
 	if err != nil {
-		return hydratedSchema, err
+		return result, err
 	}
 	resp, doErr := client.Do(request)
 	if doErr != nil {
-		return hydratedSchema, doErr
+		return result, doErr
 	}
+	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		return hydratedSchema, errors.New("received non-200 response getting ref " + resp.Status + " " + schemaRefValue)
+		return result, errors.New("received non-200 response getting ref " + resp.Status + " " + url)
 	}
 
-	defer resp.Body.Close()
-
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return hydratedSchema, err
-	}
-	err = json.Unmarshal(body, &referencedSchema)
-	if err != nil {
-		return hydratedSchema, err
+		return result, err
 	}
-
-	hydratedSchema, err = replaceRef(schema, referencedSchema, opts)
-	return hydratedSchema, err
+	err = json.Unmarshal(body, &result)
+	return result, err
 }
 
-func dereferenceFilePathRef(hydratedSchema map[string]any, schema map[string]any, schemaRefValue string, opts DereferenceOptions) (map[string]any, error) {
+func dereferenceFilePathRef(hydratedSchema map[string]any, schema map[string]any, schemaRefValue string, fragment string, opts DereferenceOptions) (map[string]any, error) {
 	var referencedSchema map[string]any
 	var schemaRefDir string
 	schemaRefAbsPath, err := filepath.Abs(filepath.Join(opts.Cwd, schemaRefValue))
@@ -186,7 +284,16 @@ func dereferenceFilePathRef(hydratedSchema map[string]any, schema map[string]any
 	}
 
 	schemaRefDir = filepath.Dir(schemaRefAbsPath)
-	referencedSchema, readErr := readJSONFile(schemaRefAbsPath)
+	if opts.OnFile != nil {
+		opts.OnFile(schemaRefAbsPath)
+	}
+	opts, err = opts.enter(refKey(schemaRefAbsPath, fragment))
+	if err != nil {
+		return hydratedSchema, err
+	}
+	referencedSchema, readErr := opts.Cache.load(schemaRefAbsPath, func() (map[string]any, error) {
+		return readJSONFile(schemaRefAbsPath)
+	})
 
 	if readErr != nil {
 		return hydratedSchema, readErr
@@ -194,6 +301,10 @@ func dereferenceFilePathRef(hydratedSchema map[string]any, schema map[string]any
 
 	var replaceErr error
 	opts.Cwd = schemaRefDir
+	opts.document, opts.documentKey, opts.baseURL = referencedSchema, schemaRefAbsPath, nil
+	if referencedSchema, readErr = refTarget(referencedSchema, refKey(schemaRefValue, fragment), fragment); readErr != nil {
+		return hydratedSchema, readErr
+	}
 	hydratedSchema, replaceErr = replaceRef(schema, referencedSchema, opts)
 	if replaceErr != nil {
 		return hydratedSchema, replaceErr
@@ -201,6 +312,65 @@ func dereferenceFilePathRef(hydratedSchema map[string]any, schema map[string]any
 	return hydratedSchema, nil
 }
 
+// enter returns opts for resolving the document ref points to, or an error if ref
+// is already being resolved further up, which would recurse forever.
+func (opts DereferenceOptions) enter(ref string) (DereferenceOptions, error) {
+	if slices.Contains(opts.chain, ref) {
+		return opts, fmt.Errorf("circular $ref: %s -> %s", strings.Join(opts.chain, " -> "), ref)
+	}
+	opts.chain = append(slices.Clone(opts.chain), ref)
+	return opts, nil
+}
+
+// refKey identifies the target of a ref, for detecting cycles.
+func refKey(documentKey string, fragment string) string {
+	if fragment == "" {
+		return documentKey
+	}
+	return documentKey + "#" + fragment
+}
+
+// refTarget returns a copy of the schema that fragment, a JSON Pointer, points to in
+// document. A copy is returned because dereferencing modifies the maps it walks, and
+// the document has to stay as it was for the other refs into it.
+func refTarget(document any, ref string, fragment string) (map[string]any, error) {
+	pointer, err := url.PathUnescape(fragment)
+	if err != nil {
+		return nil, fmt.Errorf("invalid fragment in $ref %s: %w", ref, err)
+	}
+	if pointer != "" && !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("unable to resolve ref: %s, only JSON Pointer fragments like #/$defs/name are supported", ref)
+	}
+
+	target := document
+	if pointer != "" {
+		for token := range strings.SplitSeq(pointer[1:], "/") {
+			token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+			switch node := target.(type) {
+			case map[string]any:
+				var ok bool
+				if target, ok = node[token]; !ok {
+					return nil, fmt.Errorf("unable to resolve ref: %s, %q not found", ref, token)
+				}
+			case []any:
+				index, atoiErr := strconv.Atoi(token)
+				if atoiErr != nil || index < 0 || index >= len(node) {
+					return nil, fmt.Errorf("unable to resolve ref: %s, %q is not an index of the array", ref, token)
+				}
+				target = node[index]
+			default:
+				return nil, fmt.Errorf("unable to resolve ref: %s, %q not found", ref, token)
+			}
+		}
+	}
+
+	targetSchema, ok := copyJSON(target).(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("unable to resolve ref: %s, it doesn't point to a schema object", ref)
+	}
+	return targetSchema, nil
+}
+
 func getValue(anyVal any) reflect.Value {
 	val := reflect.ValueOf(anyVal)
 
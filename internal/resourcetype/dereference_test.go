@@ -3,6 +3,7 @@ package resourcetype_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -10,6 +11,7 @@ import (
 	"path"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/massdriver-cloud/mass/internal/resourcetype"
 )
@@ -52,12 +54,60 @@ func TestDereferenceSchema(t *testing.T) {
 			},
 		},
 		{
-			Name:  "Does not dereference fragment (#) refs",
-			Input: jsonDecode(`{"$ref": "#/its-in-this-file"}`),
-			Expected: map[string]string{
-				"$ref": "#/its-in-this-file",
+			Name:                "Reports fragment (#) refs that aren't in the document",
+			Input:               jsonDecode(`{"$ref": "#/its-in-this-file"}`),
+			ExpectedErrorSuffix: `unable to resolve ref: #/its-in-this-file, "its-in-this-file" not found`,
+		},
+		{
+			Name:  "Dereferences fragment (#) refs into the document",
+			Input: jsonDecode(`{"properties": {"name": {"$ref": "#/$defs/name", "title": "Name"}}, "$defs": {"name": {"type": "string"}}}`),
+			Expected: map[string]any{
+				"$defs": map[string]any{"name": map[string]any{"type": "string"}},
+				"properties": map[string]any{
+					"name": map[string]any{"title": "Name", "type": "string"},
+				},
+			},
+		},
+		{
+			Name:  "Leaves recursive fragment (#) refs in the document as-is",
+			Input: jsonDecode(`{"$ref": "#/definitions/node", "definitions": {"node": {"properties": {"child": {"$ref": "#/definitions/node"}, "root": {"$ref": "#"}}}}}`),
+			Expected: map[string]any{
+				"definitions": map[string]any{"node": map[string]any{"properties": map[string]any{
+					"child": map[string]any{"properties": map[string]any{
+						"child": map[string]any{"$ref": "#/definitions/node"},
+						"root":  map[string]any{"$ref": "#"},
+					}},
+					"root": map[string]any{"$ref": "#"},
+				}}},
+				"properties": map[string]any{
+					"child": map[string]any{"$ref": "#/definitions/node"},
+					"root":  map[string]any{"$ref": "#"},
+				},
 			},
 		},
+		{
+			Name:  "Dereferences a definition from a library file",
+			Input: jsonDecode(`{"properties": {"tagged": {"$ref": "./testdata/dereference/library/definitions.json#/definitions/tagged"}, "count": {"$ref": "./testdata/dereference/library/definitions.json#/$defs/a~1b"}}}`),
+			Expected: map[string]any{
+				"properties": map[string]any{
+					"count": map[string]any{"type": "integer"},
+					"tagged": map[string]any{"properties": map[string]any{
+						"aws":  map[string]any{"id": "fake-schema-id"},
+						"name": map[string]any{"maxLength": 10, "type": "string"},
+					}},
+				},
+			},
+		},
+		{
+			Name:                "Reports definitions missing from a library file",
+			Input:               jsonDecode(`{"$ref": "./testdata/dereference/library/definitions.json#/definitions/missing"}`),
+			ExpectedErrorSuffix: `unable to resolve ref: ./testdata/dereference/library/definitions.json#/definitions/missing, "missing" not found`,
+		},
+		{
+			Name:                "Reports anchor fragments",
+			Input:               jsonDecode(`{"$ref": "./testdata/dereference/library/definitions.json#name"}`),
+			ExpectedErrorSuffix: "only JSON Pointer fragments like #/$defs/name are supported",
+		},
 		{
 			Name:  "Dereferences $refs in a list",
 			Input: jsonDecode(`{"list": ["string", {"$ref": "./testdata/dereference/aws-example.json"}]}`),
@@ -167,6 +217,10 @@ func TestDereferenceSchema(t *testing.T) {
 				if _, err := w.Write([]byte(`{"foo":"bar"}`)); err != nil {
 					t.Fatalf("Failed to write response: %v", err)
 				}
+			case "/schemas/library.json":
+				if _, err := w.Write([]byte(`{"$defs": {"named": {"properties": {"name": {"$ref": "#/$defs/name"}, "sibling": {"$ref": "../endpoint"}}}, "name": {"type": "string"}}}`)); err != nil {
+					t.Fatalf("Failed to write response: %v", err)
+				}
 			default:
 				w.WriteHeader(http.StatusNotFound)
 				_, _ = w.Write([]byte(`404 - not found`))
@@ -191,6 +245,22 @@ func TestDereferenceSchema(t *testing.T) {
 			t.Errorf("got %v, want %v", got, expected)
 		}
 
+		// fragments point into the fetched document, and relative refs in it are URLs
+		input = jsonDecode(fmt.Sprintf(`{"$ref":"%s/schemas/library.json#/$defs/named"}`, testServer.URL))
+		got, err := resourcetype.DereferenceSchema(input, opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected = map[string]any{
+			"properties": map[string]any{
+				"name":    map[string]any{"type": "string"},
+				"sibling": map[string]any{"foo": "bar"},
+			},
+		}
+		if fmt.Sprint(got) != fmt.Sprint(expected) {
+			t.Errorf("got %v, want %v", got, expected)
+		}
+
 		input = jsonDecode(fmt.Sprintf(`{"$ref":"%s/not-found"}`, testServer.URL))
 		_, gotErr := resourcetype.DereferenceSchema(input, opts)
 		expectedErrPrefix := "received non-200 response getting ref 404 Not Found"
@@ -201,6 +271,129 @@ func TestDereferenceSchema(t *testing.T) {
 	})
 }
 
+func TestDereferenceSchemaOnFile(t *testing.T) {
+	wd, _ := os.Getwd()
+	visited := []string{}
+	opts := resourcetype.DereferenceOptions{
+		Cwd:    ".",
+		OnFile: func(path string) { visited = append(visited, path) },
+	}
+
+	input := jsonDecode(`{"key": {"$ref": "./testdata/dereference/ref-aws-example.json"}}`)
+	if _, err := resourcetype.DereferenceSchema(input, opts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{
+		path.Join(wd, "testdata/dereference/ref-aws-example.json"),
+		path.Join(wd, "testdata/dereference/aws-example.json"),
+	}
+	if fmt.Sprint(visited) != fmt.Sprint(want) {
+		t.Errorf("got %v, want %v", visited, want)
+	}
+}
+
+func TestDereferenceSchemaCycles(t *testing.T) {
+	wd, _ := os.Getwd()
+	cycleA := path.Join(wd, "testdata/dereference/cycle-a.json")
+	cycleB := path.Join(wd, "testdata/dereference/cycle-b.json")
+
+	input := jsonDecode(`{"$ref": "./testdata/dereference/cycle-a.json"}`)
+	_, err := resourcetype.DereferenceSchema(input, resourcetype.DereferenceOptions{Cwd: "."})
+	want := fmt.Sprintf("circular $ref: %s -> %s -> %s", cycleA, cycleB, cycleA)
+	if err == nil || err.Error() != want {
+		t.Errorf("got %v, want %v", err, want)
+	}
+
+	resolver := func(_ context.Context, name string) (map[string]any, error) {
+		return map[string]any{"schema": map[string]any{"properties": map[string]any{"self": map[string]any{"$ref": name}}}}, nil
+	}
+	input = jsonDecode(`{"$ref": "massdriver/recursive"}`)
+	_, err = resourcetype.DereferenceSchema(input, resourcetype.DereferenceOptions{Resolver: resolver})
+	want = "circular $ref: massdriver/recursive -> massdriver/recursive"
+	if err == nil || err.Error() != want {
+		t.Errorf("got %v, want %v", err, want)
+	}
+}
+
+func TestDereferenceSchemaCache(t *testing.T) {
+	calls := map[string]int{}
+	resolver := func(_ context.Context, name string) (map[string]any, error) {
+		calls[name]++
+		return map[string]any{
+			"$id":    name,
+			"schema": map[string]any{"properties": map[string]any{"id": map[string]any{"type": "string"}}},
+		}, nil
+	}
+
+	input := jsonDecode(`{"properties": {
+		"first": {"$ref": "massdriver/network"},
+		"second": {"$ref": "massdriver/network"},
+		"files": {"$ref": "./testdata/dereference/ref-twice.json"}
+	}}`)
+	opts := resourcetype.DereferenceOptions{Resolver: resolver, Cwd: ".", StripID: true, Cache: resourcetype.NewRefCache()}
+	got, err := resourcetype.DereferenceSchema(input, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// repeated refs resolve to the same schema, whether or not they were cached
+	want := `map[properties:map[files:map[properties:map[first:map[properties:map[id:fake-schema-id]] second:map[properties:map[id:fake-schema-id]]]] first:map[properties:map[id:map[type:string]]] second:map[properties:map[id:map[type:string]]]]]`
+	if fmt.Sprint(got) != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// a shared cache dedupes across calls too
+	if _, err = resourcetype.DereferenceSchema(jsonDecode(`{"$ref": "massdriver/network"}`), opts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls["massdriver/network"] != 1 {
+		t.Errorf("resolver called %d times, want 1", calls["massdriver/network"])
+	}
+}
+
+func TestDiskCachedResolver(t *testing.T) {
+	dir := t.TempDir()
+	calls := 0
+	fail := false
+	resolver := func(_ context.Context, name string) (map[string]any, error) {
+		if fail {
+			return nil, errors.New("offline")
+		}
+		calls++
+		return map[string]any{"name": name, "calls": calls}, nil
+	}
+
+	cached := resourcetype.NewDiskCachedResolver(resolver, dir, time.Hour)
+	for range 2 {
+		got, err := cached(context.Background(), "massdriver/network")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got["name"] != "massdriver/network" {
+			t.Errorf("got %v", got)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("resolver called %d times, want 1", calls)
+	}
+
+	// expired entries are fetched again, or used as-is when the fetch fails
+	expired := resourcetype.NewDiskCachedResolver(resolver, dir, 0)
+	got, err := expired(context.Background(), "massdriver/network")
+	if err != nil || fmt.Sprint(got["calls"]) != "2" {
+		t.Errorf("got %v, %v, want a refetched resource type", got, err)
+	}
+	fail = true
+	got, err = expired(context.Background(), "massdriver/network")
+	if err != nil || fmt.Sprint(got["calls"]) != "2" {
+		t.Errorf("got %v, %v, want the expired resource type", got, err)
+	}
+	if _, err = expired(context.Background(), "massdriver/bucket"); err == nil || err.Error() != "offline" {
+		t.Errorf("got %v, want offline", err)
+	}
+}
+
 func jsonDecode(data string) map[string]any {
 	var result map[string]any
 	if err := json.Unmarshal([]byte(data), &result); err != nil {
@@ -0,0 +1,49 @@
+package resourcetype
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/massdriver-cloud/massdriver-sdk-go/massdriver"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// Diff compares the published schema of the resource type named name with the
+// resource type at path, returning a unified diff of the two as indented JSON. The
+// diff is empty when they match.
+func Diff(ctx context.Context, mdClient *massdriver.Client, name string, path string) (string, error) {
+	published, getErr := Get(ctx, mdClient, name)
+	if getErr != nil {
+		return "", fmt.Errorf("failed to get resource type %s: %w", name, getErr)
+	}
+
+	local, readErr := Read(ctx, mdClient, path)
+	if readErr != nil {
+		return "", fmt.Errorf("failed to read resource type: %w", readErr)
+	}
+
+	return DiffSchemas(published.Schema, local, name, path)
+}
+
+// DiffSchemas returns a unified diff of two resource type schemas as indented JSON,
+// labeled with fromName and toName. The diff is empty when they match.
+func DiffSchemas(from, to map[string]any, fromName, toName string) (string, error) {
+	// maps marshal with sorted keys, so key order never shows up as a change
+	fromJSON, fromErr := json.MarshalIndent(from, "", "  ")
+	if fromErr != nil {
+		return "", fromErr
+	}
+	toJSON, toErr := json.MarshalIndent(to, "", "  ")
+	if toErr != nil {
+		return "", toErr
+	}
+
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(fromJSON) + "\n"),
+		B:        difflib.SplitLines(string(toJSON) + "\n"),
+		FromFile: fromName,
+		ToFile:   toName,
+		Context:  3,
+	})
+}
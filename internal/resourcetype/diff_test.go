@@ -0,0 +1,91 @@
+package resourcetype_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/massdriver-cloud/mass/internal/api"
+	"github.com/massdriver-cloud/mass/internal/resourcetype"
+
+	"github.com/massdriver-cloud/massdriver-sdk-go/massdriver"
+	"github.com/massdriver-cloud/massdriver-sdk-go/massdriver/gql/gqltest"
+)
+
+func TestDiff(t *testing.T) {
+	type test struct {
+		name      string
+		published map[string]any
+		want      []string
+	}
+	tests := []test{
+		{
+			name: "matches the local file",
+			published: map[string]any{
+				"$schema": "http://json-schema.org/draft-07/schema",
+				"$md":     map[string]any{"name": "foo"},
+				"type":    "object",
+				"title":   "Test Resource Type",
+				"properties": map[string]any{
+					"foo": map[string]any{"type": "object"},
+					"bar": map[string]any{"type": "object"},
+				},
+			},
+			want: nil,
+		},
+		{
+			name: "shows added and removed lines",
+			published: map[string]any{
+				"$schema": "http://json-schema.org/draft-07/schema",
+				"$md":     map[string]any{"name": "foo"},
+				"type":    "object",
+				"title":   "Old Title",
+				"properties": map[string]any{
+					"foo": map[string]any{"type": "object"},
+				},
+			},
+			want: []string{
+				"--- foo\n",
+				"+++ testdata/simple-resource.json\n",
+				`+    "bar": {`,
+				`-  "title": "Old Title",`,
+				`+  "title": "Test Resource Type",`,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := gqltest.NewClient(
+				gqltest.RespondWithData(map[string]any{
+					"resourceType": map[string]any{
+						"id":     "foo",
+						"name":   "foo",
+						"schema": tc.published,
+					},
+				}),
+			)
+			t.Cleanup(api.SetTransportForTest(mock))
+			mdClient, err := massdriver.NewClient(
+				massdriver.WithGQLClient(mock),
+				massdriver.WithOrganizationID("test-org"),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := resourcetype.Diff(t.Context(), mdClient, "foo", "testdata/simple-resource.json")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tc.want == nil && got != "" {
+				t.Errorf("expected no diff, got:\n%s", got)
+			}
+			for _, want := range tc.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("expected diff to contain %q, got:\n%s", want, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package resourcetype
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/osteele/liquid"
+)
+
+// ExportDataBinding is the name resource data is bound to in export templates, as in
+// {{ artifact.id }}.
+const ExportDataBinding = "artifact"
+
+// RenderExportOptions controls RenderExport.
+type RenderExportOptions struct {
+	// Format is the fileFormat of the export to render. It may be omitted when the
+	// resource type has a single export.
+	Format string
+	// Data is the resource data the template is rendered with.
+	Data map[string]any
+	// Strict fails the render when the template uses a variable that isn't in Data,
+	// instead of rendering it empty.
+	Strict bool
+}
+
+// RenderExport renders one of the export templates of the massdriver.yaml resource type
+// at path with the given resource data, the way the platform does when a resource is
+// downloaded, so templates can be tested without publishing.
+func RenderExport(path string, opts RenderExportOptions) (string, error) {
+	built, err := Build(path)
+	if err != nil {
+		return "", err
+	}
+	md, _ := built["$md"].(map[string]any)
+	exports, _ := md["export"].([]map[string]any)
+	if len(exports) == 0 {
+		return "", errors.New("the resource type has no exports to render")
+	}
+
+	formats := make([]string, 0, len(exports))
+	for _, export := range exports {
+		formats = append(formats, fmt.Sprint(export["fileFormat"]))
+	}
+	var export map[string]any
+	switch {
+	case opts.Format != "":
+		index := slices.Index(formats, opts.Format)
+		if index == -1 {
+			return "", fmt.Errorf("the resource type has no %s export, available formats: %s", opts.Format, strings.Join(formats, ", "))
+		}
+		export = exports[index]
+	case len(exports) == 1:
+		export = exports[0]
+	default:
+		return "", fmt.Errorf("the resource type has several exports, choose a format: %s", strings.Join(formats, ", "))
+	}
+
+	if lang := fmt.Sprint(export["templateLang"]); lang != "liquid" {
+		return "", fmt.Errorf("unsupported template language %q, only liquid templates can be rendered", lang)
+	}
+	template, _ := export["template"].(string)
+
+	engine := liquid.NewEngine()
+	if opts.Strict {
+		engine.StrictVariables()
+	}
+	rendered, renderErr := engine.ParseAndRenderString(template, map[string]any{ExportDataBinding: opts.Data})
+	if renderErr != nil {
+		return "", fmt.Errorf("failed to render the %s export: %w", export["fileFormat"], renderErr)
+	}
+	return rendered, nil
+}
@@ -0,0 +1,77 @@
+package resourcetype_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/massdriver-cloud/mass/internal/resourcetype"
+)
+
+func TestRenderExport(t *testing.T) {
+	withExports := filepath.Join("testdata", "massdriver-yaml-resource", "massdriver.yaml")
+	data := map[string]any{"id": "res-123", "token": "s3cret"}
+
+	tests := []struct {
+		name    string
+		path    string
+		opts    resourcetype.RenderExportOptions
+		want    string
+		wantErr string
+	}{
+		{
+			name: "renders the export of a format",
+			path: withExports,
+			opts: resourcetype.RenderExportOptions{Format: "yaml", Data: data},
+			want: "apiVersion: v1\nkind: Config\nmetadata:\n  name: res-123\ncredentials:\n  token: s3cret\n",
+		},
+		{
+			name: "renders the only export without a format",
+			path: withExports,
+			opts: resourcetype.RenderExportOptions{Data: data},
+			want: "apiVersion: v1\nkind: Config\nmetadata:\n  name: res-123\ncredentials:\n  token: s3cret\n",
+		},
+		{
+			name: "renders missing data empty",
+			path: withExports,
+			opts: resourcetype.RenderExportOptions{Data: map[string]any{"id": "res-123"}},
+			want: "apiVersion: v1\nkind: Config\nmetadata:\n  name: res-123\ncredentials:\n  token: \n",
+		},
+		{
+			name:    "strict fails on missing data",
+			path:    withExports,
+			opts:    resourcetype.RenderExportOptions{Data: map[string]any{"id": "res-123"}, Strict: true},
+			wantErr: "undefined variable",
+		},
+		{
+			name:    "unknown format",
+			path:    withExports,
+			opts:    resourcetype.RenderExportOptions{Format: "json", Data: data},
+			wantErr: "the resource type has no json export, available formats: yaml",
+		},
+		{
+			name:    "no exports",
+			path:    filepath.Join("testdata", "massdriver-yaml-simple", "massdriver.yaml"),
+			opts:    resourcetype.RenderExportOptions{Data: data},
+			wantErr: "the resource type has no exports to render",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := resourcetype.RenderExport(tc.path, tc.opts)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("got error %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
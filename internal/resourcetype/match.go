@@ -0,0 +1,80 @@
+package resourcetype
+
+import (
+	"strings"
+)
+
+// MatchScore reports how well an object schema, such as one generated from an IaC
+// variable, structurally fits a resource type's schema. Every property the object
+// declares must also be declared by the resource type with a compatible type, since
+// the IaC only reads a subset of the resource. The score is the number of matched
+// properties, nested ones included; 0 means the schemas don't match.
+func MatchScore(sch map[string]any, resourceTypeSchema map[string]any) int {
+	props, _ := sch["properties"].(map[string]any)
+	rtProps, _ := resourceTypeSchema["properties"].(map[string]any)
+	if len(props) == 0 || len(rtProps) == 0 {
+		return 0
+	}
+
+	score := 0
+	for name, prop := range props {
+		rtProp, exists := rtProps[name]
+		if !exists {
+			return 0
+		}
+		propSchema, _ := prop.(map[string]any)
+		rtPropSchema, _ := rtProp.(map[string]any)
+		propScore := matchProperty(propSchema, rtPropSchema)
+		if propScore == 0 {
+			return 0
+		}
+		score += propScore
+	}
+	return score
+}
+
+func matchProperty(prop, rtProp map[string]any) int {
+	propType, _ := prop["type"].(string)
+	rtType, _ := rtProp["type"].(string)
+	// untyped properties, or resource type properties that are still a $ref, can't be
+	// compared, so give them the benefit of the doubt
+	if propType == "" || rtType == "" {
+		return 1
+	}
+
+	switch {
+	case propType == "object" && rtType == "object":
+		if _, hasProps := prop["properties"]; !hasProps {
+			return 1
+		}
+		if _, rtHasProps := rtProp["properties"]; !rtHasProps {
+			return 1
+		}
+		if nested := MatchScore(prop, rtProp); nested > 0 {
+			return 1 + nested
+		}
+		return 0
+	case propType == "array" && rtType == "array":
+		items, _ := prop["items"].(map[string]any)
+		rtItems, _ := rtProp["items"].(map[string]any)
+		if items == nil || rtItems == nil {
+			return 1
+		}
+		return matchProperty(items, rtItems)
+	case propType == rtType, propType == "number" && rtType == "integer":
+		return 1
+	}
+	return 0
+}
+
+// NameMatches reports whether a variable name refers to a resource type by name, e.g.
+// "network" or "vpc_network" for "massdriver/network".
+func NameMatches(variableName string, resourceTypeID string) bool {
+	_, shortName, _ := strings.Cut(resourceTypeID, "/")
+	if shortName == "" {
+		shortName = resourceTypeID
+	}
+	shortName = strings.ReplaceAll(shortName, "-", "_")
+	variableName = strings.ReplaceAll(variableName, "-", "_")
+	return variableName == shortName || strings.HasSuffix(variableName, "_"+shortName)
+}
@@ -0,0 +1,132 @@
+package resourcetype_test
+
+import (
+	"testing"
+
+	"github.com/massdriver-cloud/mass/internal/resourcetype"
+)
+
+func TestMatchScore(t *testing.T) {
+	network := map[string]any{
+		"properties": map[string]any{
+			"id":   map[string]any{"type": "string"},
+			"cidr": map[string]any{"type": "string"},
+			"subnets": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"id":   map[string]any{"type": "string"},
+						"zone": map[string]any{"type": "string"},
+					},
+				},
+			},
+			"tags":   map[string]any{"$ref": "massdriver/tags"},
+			"mtu":    map[string]any{"type": "integer"},
+			"policy": map[string]any{"type": "object"},
+		},
+	}
+
+	type test struct {
+		name     string
+		variable map[string]any
+		want     int
+	}
+	tests := []test{
+		{
+			name: "subset",
+			variable: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"id":   map[string]any{"type": "string"},
+					"cidr": map[string]any{"type": "string"},
+				},
+			},
+			want: 2,
+		},
+		{
+			name: "nested array of objects",
+			variable: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"subnets": map[string]any{
+						"type": "array",
+						"items": map[string]any{
+							"type":       "object",
+							"properties": map[string]any{"zone": map[string]any{"type": "string"}},
+						},
+					},
+				},
+			},
+			want: 2,
+		},
+		{
+			name: "untyped and number properties are compatible",
+			variable: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"tags":   map[string]any{"type": "object"},
+					"mtu":    map[string]any{"type": "number"},
+					"policy": map[string]any{"type": "object", "properties": map[string]any{"name": map[string]any{"type": "string"}}},
+				},
+			},
+			want: 3,
+		},
+		{
+			name: "undeclared property",
+			variable: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"id":     map[string]any{"type": "string"},
+					"region": map[string]any{"type": "string"},
+				},
+			},
+			want: 0,
+		},
+		{
+			name: "mismatched type",
+			variable: map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"cidr": map[string]any{"type": "number"}},
+			},
+			want: 0,
+		},
+		{
+			name:     "no properties",
+			variable: map[string]any{"type": "object"},
+			want:     0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := resourcetype.MatchScore(tc.variable, network)
+			if got != tc.want {
+				t.Errorf("got %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNameMatches(t *testing.T) {
+	type test struct {
+		variable     string
+		resourceType string
+		want         bool
+	}
+	tests := []test{
+		{variable: "network", resourceType: "massdriver/network", want: true},
+		{variable: "vpc_network", resourceType: "massdriver/network", want: true},
+		{variable: "kubernetes_cluster", resourceType: "massdriver/kubernetes-cluster", want: true},
+		{variable: "bucket", resourceType: "bucket", want: true},
+		{variable: "subnetwork", resourceType: "massdriver/network", want: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.variable+"/"+tc.resourceType, func(t *testing.T) {
+			if got := resourcetype.NameMatches(tc.variable, tc.resourceType); got != tc.want {
+				t.Errorf("got %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package resourcetype
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates/new
+var newTemplates embed.FS
+
+var resourceTypeNamePattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// NewOptions holds the values a new resource type is scaffolded with.
+type NewOptions struct {
+	// Name of the resource type, like postgres-credentials.
+	Name string
+	// Label shown in the Massdriver UI. Defaults to the name in title case.
+	Label string
+	// OutputDir the resource type is created in. Defaults to a directory named after it.
+	OutputDir string
+}
+
+// New scaffolds a resource type in the massdriver.yaml format: the massdriver.yaml with a
+// starter schema, an instructions markdown file and an export template. Files ending in
+// .tmpl are rendered with the options; the rest are copied as-is. It returns the
+// directory the resource type was created in, which must not already have files in it.
+func New(opts NewOptions) (string, error) {
+	if !resourceTypeNamePattern.MatchString(opts.Name) {
+		return "", fmt.Errorf("invalid resource type name %q, use lowercase letters, digits and dashes, e.g. postgres-credentials", opts.Name)
+	}
+	if opts.Label == "" {
+		opts.Label = titleCase(opts.Name)
+	}
+	if opts.OutputDir == "" {
+		opts.OutputDir = opts.Name
+	}
+
+	if entries, err := os.ReadDir(opts.OutputDir); err == nil && len(entries) > 0 {
+		return "", fmt.Errorf("directory %s already exists and isn't empty", opts.OutputDir)
+	} else if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return "", err
+	}
+
+	root := "templates/new"
+	err := fs.WalkDir(newTemplates, root, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil || d.IsDir() {
+			return walkErr
+		}
+		content, readErr := newTemplates.ReadFile(path)
+		if readErr != nil {
+			return readErr
+		}
+		rel := strings.TrimPrefix(path, root+"/")
+		if strings.HasSuffix(rel, ".tmpl") {
+			rel = strings.TrimSuffix(rel, ".tmpl")
+			tmpl, parseErr := template.New(rel).Parse(string(content))
+			if parseErr != nil {
+				return parseErr
+			}
+			var buf bytes.Buffer
+			if execErr := tmpl.Execute(&buf, opts); execErr != nil {
+				return execErr
+			}
+			content = buf.Bytes()
+		}
+
+		outPath := filepath.Join(opts.OutputDir, filepath.FromSlash(rel))
+		if mkdirErr := os.MkdirAll(filepath.Dir(outPath), 0750); mkdirErr != nil {
+			return mkdirErr
+		}
+		return os.WriteFile(outPath, content, 0600)
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to scaffold resource type: %w", err)
+	}
+	return opts.OutputDir, nil
+}
+
+// titleCase turns a name like postgres-credentials into Postgres Credentials.
+func titleCase(name string) string {
+	words := strings.Split(name, "-")
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,93 @@
+package resourcetype_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/massdriver-cloud/mass/internal/jsonschema"
+	"github.com/massdriver-cloud/mass/internal/resourcetype"
+)
+
+func TestNew(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "postgres-credentials")
+	got, err := resourcetype.New(resourcetype.NewOptions{Name: "postgres-credentials", OutputDir: dir})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != dir {
+		t.Errorf("got directory %s, want %s", got, dir)
+	}
+
+	for _, file := range []string{"massdriver.yaml", "instructions/getting-started.md", "exports/config.yaml.liquid"} {
+		if _, statErr := os.Stat(filepath.Join(dir, file)); statErr != nil {
+			t.Errorf("expected %s to be created: %v", file, statErr)
+		}
+	}
+
+	built, err := resourcetype.Build(filepath.Join(dir, "massdriver.yaml"))
+	if err != nil {
+		t.Fatalf("scaffolded resource type doesn't build: %v", err)
+	}
+	md, _ := built["$md"].(map[string]any)
+	if md["name"] != "postgres-credentials" || md["label"] != "Postgres Credentials" {
+		t.Errorf("got name %v and label %v", md["name"], md["label"])
+	}
+
+	sch, err := jsonschema.LoadSchemaFromFile("testdata/resourcetype-schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = jsonschema.ValidateGo(sch, built); err != nil {
+		t.Errorf("scaffolded resource type isn't valid: %v", err)
+	}
+}
+
+func TestNewLabel(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := resourcetype.New(resourcetype.NewOptions{Name: "db", Label: `Database: "primary"`, OutputDir: dir}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	built, err := resourcetype.Build(filepath.Join(dir, "massdriver.yaml"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	md, _ := built["$md"].(map[string]any)
+	if md["label"] != `Database: "primary"` || built["title"] != `Database: "primary"` {
+		t.Errorf("got label %v and title %v", md["label"], built["title"])
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	nonEmpty := t.TempDir()
+	if err := os.WriteFile(filepath.Join(nonEmpty, "massdriver.yaml"), []byte("name: existing\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		opts    resourcetype.NewOptions
+		wantErr string
+	}{
+		{
+			name:    "invalid name",
+			opts:    resourcetype.NewOptions{Name: "Postgres_Credentials", OutputDir: t.TempDir()},
+			wantErr: `invalid resource type name "Postgres_Credentials"`,
+		},
+		{
+			name:    "existing files",
+			opts:    resourcetype.NewOptions{Name: "postgres", OutputDir: nonEmpty},
+			wantErr: "already exists and isn't empty",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := resourcetype.New(tc.opts)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("got error %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
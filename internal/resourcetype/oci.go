@@ -0,0 +1,208 @@
+package resourcetype
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/massdriver-cloud/massdriver-sdk-go/massdriver"
+	"github.com/massdriver-cloud/massdriver-sdk-go/massdriver/platform/ocirepos"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	oras "oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/file"
+	"oras.land/oras-go/v2/content/memory"
+)
+
+// OCIArtifactType is the artifact type of resource type manifests in the registry.
+const OCIArtifactType = "application/vnd.massdriver.resource-type.v1+json"
+
+var versionFormat = regexp.MustCompile(`^\d+\.\d+\.\d+$`)
+
+var mediaTypesFromExt = map[string]string{
+	".json":   "application/json",
+	".yaml":   "application/yaml",
+	".yml":    "application/yaml",
+	".md":     "text/markdown",
+	".liquid": "text/plain",
+	".svg":    "image/svg+xml",
+	".png":    "image/png",
+	".jpg":    "image/jpeg",
+	".jpeg":   "image/jpeg",
+}
+
+// Package pushes every file in the resource type directory dir to store, skipping
+// hidden files and directories, and tags a manifest of them with tag. Each file is a
+// layer titled with its path relative to dir, so pulling the manifest recreates the
+// directory.
+func Package(ctx context.Context, store oras.Target, dir string, tag string) (ocispec.Descriptor, error) {
+	layers := []ocispec.Descriptor{}
+	pushed := map[string]bool{}
+	walkErr := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != dir && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		relativePath, relErr := filepath.Rel(dir, path)
+		if relErr != nil {
+			return relErr
+		}
+		data, readErr := os.ReadFile(path)
+		if readErr != nil {
+			return fmt.Errorf("reading %s: %w", path, readErr)
+		}
+
+		descriptor := content.NewDescriptorFromBytes(mediaTypesFromExt[filepath.Ext(path)], data)
+		descriptor.Annotations = map[string]string{
+			ocispec.AnnotationTitle: filepath.ToSlash(relativePath),
+		}
+		if !pushed[descriptor.Digest.String()] {
+			if pushErr := store.Push(ctx, descriptor, bytes.NewReader(data)); pushErr != nil {
+				return fmt.Errorf("pushing %s: %w", path, pushErr)
+			}
+			pushed[descriptor.Digest.String()] = true
+		}
+		layers = append(layers, descriptor)
+		return nil
+	})
+	if walkErr != nil {
+		return ocispec.Descriptor{}, walkErr
+	}
+
+	manifestDescriptor, packErr := oras.PackManifest(ctx, store, oras.PackManifestVersion1_1, OCIArtifactType, oras.PackManifestOptions{Layers: layers})
+	if packErr != nil {
+		return ocispec.Descriptor{}, packErr
+	}
+	if tagErr := store.Tag(ctx, manifestDescriptor, tag); tagErr != nil {
+		return ocispec.Descriptor{}, tagErr
+	}
+	return manifestDescriptor, nil
+}
+
+// PublishOCI packages the resource type directory of the massdriver.yaml at path and
+// pushes it to the resource type's repository in the Massdriver registry, tagged with
+// its version. Versions can't be overwritten. It returns the published version and
+// the descriptor of its manifest.
+func PublishOCI(ctx context.Context, mdClient *massdriver.Client, path string) (string, ocispec.Descriptor, error) {
+	if !IsMassdriverYAMLResourceType(path) {
+		return "", ocispec.Descriptor{}, errors.New("only massdriver.yaml resource types can be published to the registry")
+	}
+	config, readErr := readMassdriverYAML(path)
+	if readErr != nil {
+		return "", ocispec.Descriptor{}, readErr
+	}
+	if config.Name == "" {
+		return "", ocispec.Descriptor{}, errors.New("massdriver.yaml is missing name")
+	}
+	if !versionFormat.MatchString(config.Version) {
+		return "", ocispec.Descriptor{}, fmt.Errorf("invalid version %q in massdriver.yaml, must follow semantic versioning (MAJOR.MINOR.PATCH), e.g. 1.2.3", config.Version)
+	}
+
+	// validate the resource type builds before anything is pushed
+	if _, buildErr := Build(path); buildErr != nil {
+		return "", ocispec.Descriptor{}, buildErr
+	}
+
+	ociRepo, getErr := mdClient.OciRepos.Get(ctx, config.Name)
+	if getErr != nil {
+		return "", ocispec.Descriptor{}, fmt.Errorf("fetching OCI repo: %w", getErr)
+	}
+	if repoErr := ValidateRepo(ociRepo); repoErr != nil {
+		return "", ocispec.Descriptor{}, repoErr
+	}
+	for _, t := range ociRepo.Tags {
+		if t.Tag == config.Version {
+			return "", ocispec.Descriptor{}, fmt.Errorf("version %s already exists for resource type %s", config.Version, config.Name)
+		}
+	}
+
+	repo, repoErr := mdClient.OciRepos.Target(config.Name)
+	if repoErr != nil {
+		return "", ocispec.Descriptor{}, fmt.Errorf("getting repository: %w", repoErr)
+	}
+	store := memory.New()
+	descriptor, packageErr := Package(ctx, store, filepath.Dir(path), config.Version)
+	if packageErr != nil {
+		return "", ocispec.Descriptor{}, fmt.Errorf("packaging resource type: %w", packageErr)
+	}
+	if _, copyErr := oras.Copy(ctx, store, config.Version, repo, config.Version, oras.DefaultCopyOptions); copyErr != nil {
+		return "", ocispec.Descriptor{}, fmt.Errorf("publishing resource type: %w", copyErr)
+	}
+	return config.Version, descriptor, nil
+}
+
+// Pull fetches version of the resource type named name from the Massdriver registry
+// into directory. The version can also be a release channel. It returns the resolved
+// version and the descriptor of the pulled manifest.
+func Pull(ctx context.Context, mdClient *massdriver.Client, name string, version string, directory string) (string, ocispec.Descriptor, error) {
+	ociRepo, getErr := mdClient.OciRepos.Get(ctx, name)
+	if getErr != nil {
+		return "", ocispec.Descriptor{}, fmt.Errorf("failed to get OCI repo: %w", getErr)
+	}
+	if repoErr := ValidateRepo(ociRepo); repoErr != nil {
+		return "", ocispec.Descriptor{}, repoErr
+	}
+	tag := ""
+	for _, t := range ociRepo.Tags {
+		if t.Tag == version {
+			tag = version
+		}
+	}
+	for _, channel := range ociRepo.ReleaseChannels {
+		if tag == "" && channel.Name == version {
+			tag = channel.Tag
+		}
+	}
+	if tag == "" {
+		return "", ocispec.Descriptor{}, fmt.Errorf("version or release channel '%s' not found in OCI repo '%s'", version, name)
+	}
+
+	repo, repoErr := mdClient.OciRepos.Target(name)
+	if repoErr != nil {
+		return "", ocispec.Descriptor{}, repoErr
+	}
+	store, fileErr := file.New(directory)
+	if fileErr != nil {
+		return "", ocispec.Descriptor{}, fmt.Errorf("failed to create file store: %w", fileErr)
+	}
+	defer store.Close()
+
+	descriptor, copyErr := oras.Copy(ctx, repo, tag, store, tag, oras.DefaultCopyOptions)
+	if copyErr != nil {
+		return "", ocispec.Descriptor{}, fmt.Errorf("failed to pull resource type: %w", copyErr)
+	}
+	return tag, descriptor, nil
+}
+
+// ValidateRepo returns an error unless repo holds resource types, so a bundle
+// repository with the same name is never pushed to or pulled from as a resource type.
+func ValidateRepo(repo *ocirepos.OciRepo) error {
+	if repo.ArtifactType != ocirepos.ArtifactTypeResourceType {
+		kind := strings.ToLower(strings.ReplaceAll(string(repo.ArtifactType), "_", " "))
+		return fmt.Errorf("OCI repo '%s' is a %s repository, not a resource type repository", repo.Name, kind)
+	}
+	return nil
+}
+
+// ParseReference splits a <name>@<version> reference to a published resource type.
+func ParseReference(ref string) (string, string, error) {
+	at := strings.LastIndex(ref, "@")
+	if at <= 0 || at == len(ref)-1 {
+		return "", "", fmt.Errorf("invalid resource type reference %q, expected <name>@<version>", ref)
+	}
+	return ref[:at], ref[at+1:], nil
+}
@@ -0,0 +1,139 @@
+package resourcetype_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/massdriver-cloud/mass/internal/resourcetype"
+	"github.com/massdriver-cloud/massdriver-sdk-go/massdriver/platform/ocirepos"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	oras "oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content/file"
+	"oras.land/oras-go/v2/content/memory"
+)
+
+func TestPackage(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"massdriver.yaml":            "name: foo\nversion: 1.0.0\n",
+		"instructions/cli.md":        "# CLI\n",
+		"exports/config.yaml.liquid": "id: {{ artifact.id }}\n",
+		".git/HEAD":                  "ref: refs/heads/main\n",
+		".env":                       "SECRET=1\n",
+	}
+	for path, contents := range files {
+		full := filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(contents), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	store := memory.New()
+	descriptor, err := resourcetype.Package(t.Context(), store, dir, "1.0.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if descriptor.MediaType != ocispec.MediaTypeImageManifest {
+		t.Errorf("got media type %s, want %s", descriptor.MediaType, ocispec.MediaTypeImageManifest)
+	}
+
+	out := t.TempDir()
+	target, err := file.New(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer target.Close()
+	if _, err = oras.Copy(t.Context(), store, "1.0.0", target, "1.0.0", oras.DefaultCopyOptions); err != nil {
+		t.Fatalf("failed to pull package: %v", err)
+	}
+
+	for _, path := range []string{"massdriver.yaml", "instructions/cli.md", "exports/config.yaml.liquid"} {
+		got, readErr := os.ReadFile(filepath.Join(out, path))
+		if readErr != nil {
+			t.Errorf("expected %s to be packaged: %v", path, readErr)
+			continue
+		}
+		if string(got) != files[path] {
+			t.Errorf("%s: got %q, want %q", path, got, files[path])
+		}
+	}
+	for _, path := range []string{".git/HEAD", ".env"} {
+		if _, statErr := os.Stat(filepath.Join(out, path)); statErr == nil {
+			t.Errorf("expected hidden file %s not to be packaged", path)
+		}
+	}
+}
+
+func TestParseReference(t *testing.T) {
+	type test struct {
+		name        string
+		ref         string
+		wantName    string
+		wantVersion string
+		wantErr     bool
+	}
+	tests := []test{
+		{name: "name and version", ref: "my-org/postgres@1.2.0", wantName: "my-org/postgres", wantVersion: "1.2.0"},
+		{name: "release channel", ref: "postgres@latest", wantName: "postgres", wantVersion: "latest"},
+		{name: "missing version", ref: "postgres@", wantErr: true},
+		{name: "missing name", ref: "@1.2.0", wantErr: true},
+		{name: "no separator", ref: "postgres", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			name, version, err := resourcetype.ParseReference(tc.ref)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("expected an error for %q", tc.ref)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if name != tc.wantName || version != tc.wantVersion {
+				t.Errorf("got %s@%s, want %s@%s", name, version, tc.wantName, tc.wantVersion)
+			}
+		})
+	}
+}
+
+func TestValidateRepo(t *testing.T) {
+	type test struct {
+		name    string
+		repo    *ocirepos.OciRepo
+		wantErr string
+	}
+	tests := []test{
+		{
+			name: "resource type repository",
+			repo: &ocirepos.OciRepo{Name: "my-org/postgres", ArtifactType: ocirepos.ArtifactTypeResourceType},
+		},
+		{
+			name:    "bundle repository with the same name",
+			repo:    &ocirepos.OciRepo{Name: "my-org/postgres", ArtifactType: ocirepos.ArtifactTypeBundle},
+			wantErr: "OCI repo 'my-org/postgres' is a bundle repository, not a resource type repository",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := resourcetype.ValidateRepo(tc.repo)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tc.wantErr {
+				t.Errorf("got error %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}